@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -13,11 +14,30 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"subscriptionMService/gen/go/account"
 	"subscriptionMService/internal/app/grpcapp"
+	"subscriptionMService/internal/app/workerapp"
 	bcktgrpc "subscriptionMService/internal/clients/bucket/grpc"
+	"subscriptionMService/internal/data"
 	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/internal/notifications"
+	"subscriptionMService/internal/outbox"
+	"subscriptionMService/internal/payments"
 	"subscriptionMService/internal/planCache"
+	"subscriptionMService/internal/proration"
+	"subscriptionMService/internal/refunds"
+	"subscriptionMService/internal/retention"
+	"subscriptionMService/internal/services/dunning"
+	"subscriptionMService/internal/services/expiry"
+	"subscriptionMService/internal/services/pause"
+	"subscriptionMService/internal/services/planadmin"
+	"subscriptionMService/internal/services/planchange"
+	"subscriptionMService/internal/services/provisioning"
+	"subscriptionMService/internal/services/relay"
+	"subscriptionMService/internal/services/reminder"
+	"subscriptionMService/internal/services/renewal"
 	"subscriptionMService/internal/services/subscription"
+	"subscriptionMService/internal/services/webhook"
 	"subscriptionMService/storage/postgres"
 	"syscall"
 	"time"
@@ -33,11 +53,69 @@ type StorageDetails struct {
 }
 
 type Config struct {
-	env      string
-	DB       StorageDetails
-	GRPC     GRPCConfig
-	TokenTTL time.Duration
-	Clients  ClientsConfig
+	env           string
+	DB            StorageDetails
+	GRPC          GRPCConfig
+	TokenTTL      time.Duration
+	Clients       ClientsConfig
+	Expiry        ExpiryConfig
+	Renewal       RenewalConfig
+	Idempotency   IdempotencyConfig
+	Outbox        OutboxConfig
+	Provisioning  ProvisioningConfig
+	Notifications NotificationsConfig
+	Reminders     RemindersConfig
+	Payments      PaymentsConfig
+}
+
+type PaymentsConfig struct {
+	Gateway       string
+	WebhookSecret string
+}
+
+type NotificationsConfig struct {
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	Sender       string
+	File         string
+}
+
+type RemindersConfig struct {
+	Interval time.Duration
+}
+
+type ProvisioningConfig struct {
+	Interval   time.Duration
+	StaleAfter time.Duration
+}
+
+type OutboxConfig struct {
+	Interval   time.Duration
+	BatchSize  int
+	Lease      time.Duration
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	File       string
+}
+
+type ExpiryConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+type IdempotencyConfig struct {
+	Retention     time.Duration
+	Lease         time.Duration
+	PurgeInterval time.Duration
+}
+
+type RenewalConfig struct {
+	Interval        time.Duration
+	BatchSize       int
+	RetryAfter      time.Duration
+	DunningSchedule string
 }
 
 type Client struct {
@@ -57,7 +135,9 @@ type GRPCConfig struct {
 }
 
 type Application struct {
-	GRPCSrv *grpcapp.App
+	GRPCSrv  *grpcapp.App
+	Workers  []*workerapp.App
+	Webhooks *webhook.Receiver
 }
 
 func main() {
@@ -80,6 +160,32 @@ func main() {
 	flag.IntVar(&cfg.Clients.Bucket.Address, "bucket-client-addr", 2000, "bucket-port")
 	flag.IntVar(&cfg.GRPC.Port, "grpc-port", 3000, "grpc-port")
 	flag.DurationVar(&cfg.TokenTTL, "token-ttl", time.Hour, "GRPC's work duration")
+	flag.DurationVar(&cfg.Expiry.Interval, "expiry-interval", time.Minute, "How often expired subscriptions are scanned")
+	flag.IntVar(&cfg.Expiry.BatchSize, "expiry-batch-size", 100, "Max subscriptions expired per query")
+	flag.DurationVar(&cfg.Renewal.Interval, "renewal-interval", time.Minute, "How often subscriptions due for renewal are scanned")
+	flag.IntVar(&cfg.Renewal.BatchSize, "renewal-batch-size", 100, "Max subscriptions renewed per scan")
+	flag.DurationVar(&cfg.Idempotency.Retention, "idempotency-retention", 24*time.Hour, "How long idempotency keys replay their stored response")
+	flag.DurationVar(&cfg.Idempotency.Lease, "idempotency-lease", time.Minute, "How long a claimed idempotency key blocks retries after its request stops renewing it")
+	flag.DurationVar(&cfg.Idempotency.PurgeInterval, "idempotency-purge-interval", time.Hour, "How often expired idempotency keys are deleted")
+	flag.DurationVar(&cfg.Renewal.RetryAfter, "renewal-retry-after", 24*time.Hour, "Delay before a renewal charge is attempted again after an interrupted or failed attempt")
+	flag.StringVar(&cfg.Renewal.DunningSchedule, "dunning-schedule", "1,3,7", "Days after the paid period ended on which a past due renewal is retried, comma separated")
+	flag.DurationVar(&cfg.Outbox.Interval, "outbox-interval", 5*time.Second, "How often the outbox is scanned for events to publish")
+	flag.IntVar(&cfg.Outbox.BatchSize, "outbox-batch-size", 100, "Max outbox events published per scan")
+	flag.DurationVar(&cfg.Outbox.Lease, "outbox-lease", time.Minute, "How long a claimed outbox event is held before another replica may retry it")
+	flag.DurationVar(&cfg.Outbox.MinBackoff, "outbox-min-backoff", 5*time.Second, "Delay before the first retry of a failed publish")
+	flag.DurationVar(&cfg.Outbox.MaxBackoff, "outbox-max-backoff", time.Hour, "Maximum delay between publish retries")
+	flag.DurationVar(&cfg.Provisioning.Interval, "provisioning-interval", 5*time.Minute, "How often subscriptions stuck in pending are repaired")
+	flag.DurationVar(&cfg.Provisioning.StaleAfter, "provisioning-stale-after", 5*time.Minute, "How long a subscription may stay pending before it is compensated")
+	flag.StringVar(&cfg.Notifications.SMTPHost, "smtp-host", os.Getenv("SMTP_HOST"), "SMTP server host; notifications are only logged when empty")
+	flag.IntVar(&cfg.Notifications.SMTPPort, "smtp-port", 587, "SMTP server port")
+	flag.StringVar(&cfg.Notifications.SMTPUsername, "smtp-username", os.Getenv("SMTP_USERNAME"), "SMTP username")
+	flag.StringVar(&cfg.Notifications.SMTPPassword, "smtp-password", os.Getenv("SMTP_PASSWORD"), "SMTP password")
+	flag.StringVar(&cfg.Notifications.Sender, "smtp-sender", "Toys <no-reply@toys.local>", "SMTP sender")
+	flag.StringVar(&cfg.Notifications.File, "notifications-file", "", "Write notifications to this file instead of sending them")
+	flag.DurationVar(&cfg.Reminders.Interval, "reminder-interval", 15*time.Minute, "How often subscriptions are scanned for expiry and low balance reminders")
+	flag.StringVar(&cfg.Outbox.File, "outbox-file", "", "Also append published events to this file as JSON lines")
+	flag.StringVar(&cfg.Payments.Gateway, "payment-gateway", "wallet", "Payment gateway (wallet|fake)")
+	flag.StringVar(&cfg.Payments.WebhookSecret, "payment-webhook-secret", os.Getenv("PAYMENT_WEBHOOK_SECRET"), "Secret payment gateway webhooks are signed with")
 
 	flag.Parse()
 
@@ -97,7 +203,10 @@ func main() {
 		"port": strconv.Itoa(cfg.GRPC.Port),
 	})
 	go app.GRPCSrv.MustRun()
-	go runHTTP(cfg.GRPC.Port, logger)
+	for _, worker := range app.Workers {
+		go worker.Run()
+	}
+	go runHTTP(cfg.GRPC.Port, logger, app.Webhooks)
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
@@ -108,6 +217,9 @@ func main() {
 	})
 
 	app.GRPCSrv.Stop()
+	for _, worker := range app.Workers {
+		worker.Stop()
+	}
 }
 
 func New(log *jsonlog.Logger, grpcPort int, cfg Config, tokenTTL time.Duration, bucketClient *bcktgrpc.BucketClient) *Application {
@@ -121,13 +233,70 @@ func New(log *jsonlog.Logger, grpcPort int, cfg Config, tokenTTL time.Duration,
 
 	planCacheProvider := planCache.NewCachedPlanProvider(db, tokenTTL)
 
-	subscriptionService := subscription.New(log, db, planCacheProvider, bucketClient, tokenTTL)
-	grpcApp := grpcapp.New(log, grpcPort, subscriptionService) // добавить сервис
+	var gateway payments.PaymentGateway
+	switch cfg.Payments.Gateway {
+	case "wallet":
+		gateway = payments.NewWalletGateway(db)
+	case "fake":
+		// Webhooks settle payments, so they must never be accepted unsigned.
+		if cfg.Payments.WebhookSecret == "" {
+			log.PrintFatal(errors.New("payment-webhook-secret must be set for the fake gateway"), nil)
+		}
+		gateway = payments.NewFakeGateway(cfg.Payments.WebhookSecret)
+	default:
+		log.PrintFatal(fmt.Errorf("unknown payment gateway %q", cfg.Payments.Gateway), nil)
+	}
+	paymentProcessor := payments.NewProcessor(log, gateway, db)
+
+	subscriptionService := subscription.New(log, db, planCacheProvider, bucketClient, paymentProcessor, proration.ProportionalPolicy{}, retention.DefaultRules(), refunds.PlanPolicy{}, tokenTTL)
+	planAdmin := planadmin.New(log, db, planCacheProvider)
+	grpcApp := grpcapp.New(log, grpcPort, subscriptionService, planAdmin, db, cfg.Idempotency.Retention, cfg.Idempotency.Lease) // добавить сервис
+	expirer := expiry.New(log, db, cfg.Expiry.BatchSize)
+	renewer := renewal.New(log, db, paymentProcessor, cfg.Renewal.BatchSize, cfg.Renewal.RetryAfter)
+	dunningSchedule, err := data.ParseDunningSchedule(cfg.Renewal.DunningSchedule)
+	if err != nil {
+		log.PrintFatal(err, nil)
+	}
+	dunner := dunning.New(log, db, dunningSchedule, cfg.Renewal.BatchSize)
+	resumer := pause.New(log, db, cfg.Expiry.BatchSize)
+	planChanger := planchange.New(log, db, cfg.Renewal.BatchSize)
+
+	var notifier notifications.Notifier = notifications.LogNotifier{Log: log}
+	switch {
+	case cfg.Notifications.File != "":
+		notifier = notifications.NewFileNotifier(cfg.Notifications.File)
+	case cfg.Notifications.SMTPHost != "":
+		n := cfg.Notifications
+		notifier = notifications.NewSMTPNotifier(n.SMTPHost, n.SMTPPort, n.SMTPUsername, n.SMTPPassword, n.Sender)
+	}
+	dispatcher := notifications.NewDispatcher(log, notifier, db)
+	reminders := reminder.New(log, db, cfg.Expiry.BatchSize)
+
+	publisher := outbox.MultiPublisher{outbox.LogPublisher{Log: log}, dispatcher}
+	if cfg.Outbox.File != "" {
+		publisher = append(publisher, outbox.NewFilePublisher(cfg.Outbox.File))
+	}
+	reconciler := provisioning.New(log, db, paymentProcessor, cfg.Provisioning.StaleAfter, cfg.Expiry.BatchSize)
+	eventRelay := relay.New(log, db, publisher, cfg.Outbox.BatchSize, cfg.Outbox.Lease, cfg.Outbox.MinBackoff, cfg.Outbox.MaxBackoff)
 
-	return &Application{GRPCSrv: grpcApp}
+	return &Application{
+		GRPCSrv:  grpcApp,
+		Webhooks: webhook.New(log, gateway, db),
+		Workers: []*workerapp.App{
+			workerapp.New(log, "expiry", cfg.Expiry.Interval, expirer.ExpireDue),
+			workerapp.New(log, "renewal", cfg.Renewal.Interval, renewer.RenewDue),
+			workerapp.New(log, "dunning", cfg.Renewal.Interval, dunner.ScheduleDue),
+			workerapp.New(log, "pause", cfg.Expiry.Interval, resumer.ResumeOverdue),
+			workerapp.New(log, "planchange", cfg.Renewal.Interval, planChanger.ApplyDue),
+			workerapp.New(log, "idempotency", cfg.Idempotency.PurgeInterval, grpcapp.PurgeIdempotencyKeys(log, db, cfg.Idempotency.Retention)),
+			workerapp.New(log, "outbox", cfg.Outbox.Interval, eventRelay.PublishDue),
+			workerapp.New(log, "provisioning", cfg.Provisioning.Interval, reconciler.RepairStale),
+			workerapp.New(log, "reminder", cfg.Reminders.Interval, reminders.QueueDue),
+		},
+	}
 }
 
-func runHTTP(grpcPort int, logger *jsonlog.Logger) {
+func runHTTP(grpcPort int, logger *jsonlog.Logger, webhooks http.Handler) {
 	ctx := context.Background()
 	mux := runtime.NewServeMux()
 	opts := []grpc.DialOption{
@@ -140,15 +309,23 @@ func runHTTP(grpcPort int, logger *jsonlog.Logger) {
 			"message": "failed to start HTTP gateway",
 		})
 	}
+	if err := account.RegisterAccountHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		logger.PrintFatal(err, map[string]string{
+			"method":  "main.runHTTP",
+			"message": "failed to start HTTP gateway",
+		})
+	}
 	fs := http.FileServer(http.Dir("C:\\Users\\Еркебулан\\GolandProjects\\subsProto\\gen\\swagger")) // path where swagger.json is output
-	http.Handle("/swagger/", http.StripPrefix("/swagger/", fs))
-	http.Handle("/", mux)
+	root := http.NewServeMux()
+	root.Handle("/swagger/", http.StripPrefix("/swagger/", fs))
+	root.Handle("/webhooks/payments", webhooks)
+	root.Handle("/", mux)
 
 	logger.PrintInfo("HTTP REST gateway and Swagger docs started", map[string]string{
 		"port": "9090",
 	})
 
-	if err := http.ListenAndServe(":9090", mux); err != nil {
+	if err := http.ListenAndServe(":9090", root); err != nil {
 		logger.PrintFatal(err, map[string]string{
 			"message": "could not start http server",
 			"method":  "main.runHTTp",
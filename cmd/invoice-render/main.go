@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	_ "github.com/lib/pq"
+	"os"
+	"subscriptionMService/internal/invoices"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/storage/postgres"
+)
+
+// invoice-render writes an invoice as an HTML page, to -out or to standard
+// output, so it can be sent to a user or printed to PDF from a browser.
+func main() {
+	var dbcfg postgres.StorageDetails
+
+	host := os.Getenv("DB_HOST")
+	port := os.Getenv("DB_PORT")
+	user := os.Getenv("DB_USER")
+	pass := os.Getenv("DB_PASSWORD")
+	name := os.Getenv("DB_NAME")
+
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&client_encoding=UTF8", user, pass, host, port, name)
+
+	flag.StringVar(&dbcfg.DSN, "db-dsn", dsn, "PostgresSQL DSN")
+	flag.IntVar(&dbcfg.MaxOpenConns, "db-max-open-conns", 5, "PostgresSQL max open connections")
+	flag.IntVar(&dbcfg.MaxIdleConns, "db-max-Idle-conns", 5, "PostgresSQL max Idle connections")
+	flag.StringVar(&dbcfg.MaxIdleTime, "db-max-Idle-time", "15m", "PostgresSQl max Idle time")
+
+	number := flag.String("number", "", "Number of the invoice to render")
+	out := flag.String("out", "", "Write the page to this file instead of standard output")
+
+	flag.Parse()
+
+	logger := jsonlog.New(os.Stderr, jsonlog.LevelInfo)
+
+	if *number == "" {
+		logger.PrintFatal(fmt.Errorf("-number is required"), nil)
+	}
+
+	db, err := postgres.OpenDB(dbcfg)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+	defer db.Close()
+
+	invoice, err := db.GetInvoice(context.Background(), *number)
+	if err != nil {
+		logger.PrintFatal(err, map[string]string{
+			"method": "invoice-render.main",
+			"number": *number,
+		})
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		w, err = os.Create(*out)
+		if err != nil {
+			logger.PrintFatal(err, map[string]string{
+				"method": "invoice-render.main",
+			})
+		}
+		defer w.Close()
+	}
+
+	if err := invoices.RenderHTML(w, invoice); err != nil {
+		logger.PrintFatal(err, map[string]string{
+			"method": "invoice-render.main",
+			"number": *number,
+		})
+	}
+}
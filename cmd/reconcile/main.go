@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	_ "github.com/lib/pq"
+	"os"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/storage/postgres"
+)
+
+// reconcile compares every subscription's remaining_limit with the balance
+// derived from its ledger entries and reports any drift. It exits with status
+// 1 when drift is found so it can run from cron or CI.
+func main() {
+	var dbcfg postgres.StorageDetails
+
+	host := os.Getenv("DB_HOST")
+	port := os.Getenv("DB_PORT")
+	user := os.Getenv("DB_USER")
+	pass := os.Getenv("DB_PASSWORD")
+	name := os.Getenv("DB_NAME")
+
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&client_encoding=UTF8", user, pass, host, port, name)
+
+	flag.StringVar(&dbcfg.DSN, "db-dsn", dsn, "PostgresSQL DSN")
+	flag.IntVar(&dbcfg.MaxOpenConns, "db-max-open-conns", 5, "PostgresSQL max open connections")
+	flag.IntVar(&dbcfg.MaxIdleConns, "db-max-Idle-conns", 5, "PostgresSQL max Idle connections")
+	flag.StringVar(&dbcfg.MaxIdleTime, "db-max-Idle-time", "15m", "PostgresSQl max Idle time")
+
+	flag.Parse()
+
+	logger := jsonlog.New(os.Stdout, jsonlog.LevelInfo)
+
+	db, err := postgres.OpenDB(dbcfg)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+	defer db.Close()
+
+	drifts, err := db.FindBalanceDrift(context.Background())
+	if err != nil {
+		logger.PrintFatal(err, map[string]string{
+			"method": "reconcile.main",
+		})
+	}
+
+	for _, d := range drifts {
+		logger.PrintError(fmt.Errorf("balance drift detected"), map[string]string{
+			"subscription_id": fmt.Sprint(d.SubscriptionID),
+			"user_id":         fmt.Sprint(d.UserID),
+			"remaining_limit": fmt.Sprint(d.RemainingLimit),
+			"ledger_balance":  fmt.Sprint(d.LedgerBalance),
+			"difference":      fmt.Sprint(d.RemainingLimit - d.LedgerBalance),
+		})
+	}
+
+	logger.PrintInfo("balance reconciliation finished", map[string]string{
+		"drifted": fmt.Sprint(len(drifts)),
+	})
+
+	if len(drifts) > 0 {
+		db.Close()
+		os.Exit(1)
+	}
+}
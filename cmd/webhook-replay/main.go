@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	_ "github.com/lib/pq"
+	"os"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/internal/services/webhook"
+	"subscriptionMService/storage/postgres"
+	"time"
+)
+
+// webhook-replay applies stored payment webhooks again, for recovery after an
+// incident left events unapplied. It replays a single event given by -id, or
+// every unprocessed event received within -since; -all includes events that
+// were already processed. It exits with status 1 if any replay fails.
+func main() {
+	var dbcfg postgres.StorageDetails
+
+	host := os.Getenv("DB_HOST")
+	port := os.Getenv("DB_PORT")
+	user := os.Getenv("DB_USER")
+	pass := os.Getenv("DB_PASSWORD")
+	name := os.Getenv("DB_NAME")
+
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&client_encoding=UTF8", user, pass, host, port, name)
+
+	flag.StringVar(&dbcfg.DSN, "db-dsn", dsn, "PostgresSQL DSN")
+	flag.IntVar(&dbcfg.MaxOpenConns, "db-max-open-conns", 5, "PostgresSQL max open connections")
+	flag.IntVar(&dbcfg.MaxIdleConns, "db-max-Idle-conns", 5, "PostgresSQL max Idle connections")
+	flag.StringVar(&dbcfg.MaxIdleTime, "db-max-Idle-time", "15m", "PostgresSQl max Idle time")
+
+	id := flag.String("id", "", "Replay only the webhook with this event id")
+	since := flag.Duration("since", 24*time.Hour, "Replay webhooks received within this long")
+	all := flag.Bool("all", false, "Also replay webhooks that were processed")
+	limit := flag.Int("limit", 1000, "Max webhooks replayed")
+
+	flag.Parse()
+
+	logger := jsonlog.New(os.Stdout, jsonlog.LevelInfo)
+
+	db, err := postgres.OpenDB(dbcfg)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	var deliveries []*data.WebhookDelivery
+	if *id != "" {
+		delivery, err := db.GetWebhook(ctx, *id)
+		if err != nil {
+			logger.PrintFatal(err, map[string]string{
+				"method":   "webhook-replay.main",
+				"event_id": *id,
+			})
+		}
+		deliveries = append(deliveries, delivery)
+	} else {
+		deliveries, err = db.ListWebhooks(ctx, time.Now().Add(-*since), !*all, *limit)
+		if err != nil {
+			logger.PrintFatal(err, map[string]string{
+				"method": "webhook-replay.main",
+			})
+		}
+	}
+
+	receiver := webhook.New(logger, nil, db)
+
+	failed := 0
+	for _, delivery := range deliveries {
+		if err := receiver.Replay(ctx, delivery); err != nil {
+			failed++
+		}
+	}
+
+	logger.PrintInfo("webhook replay finished", map[string]string{
+		"replayed": fmt.Sprint(len(deliveries)),
+		"failed":   fmt.Sprint(failed),
+	})
+
+	if failed > 0 {
+		db.Close()
+		os.Exit(1)
+	}
+}
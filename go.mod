@@ -9,14 +9,14 @@ require (
 	github.com/lib/pq v1.10.9
 	github.com/spacecowboytobykty123/bucketProto v0.0.0-20250524131200-4d68350e8fb4
 	github.com/spacecowboytobykty123/subsProto v0.0.0-20250525164154-7f9b8facd641
+	google.golang.org/genproto/googleapis/api v0.0.0-20250303144028-a0af3efb3deb
 	google.golang.org/grpc v1.71.1
+	google.golang.org/protobuf v1.36.6
 )
 
 require (
 	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
-	google.golang.org/genproto/googleapis/api v0.0.0-20250303144028-a0af3efb3deb // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250303144028-a0af3efb3deb // indirect
-	google.golang.org/protobuf v1.36.6 // indirect
 )
@@ -13,6 +13,7 @@ import (
 	"subscriptionMService/internal/contextkeys"
 	subgrpc "subscriptionMService/internal/grpc/subscription"
 	"subscriptionMService/internal/jsonlog"
+	"time"
 )
 
 type App struct {
@@ -70,17 +71,23 @@ func UnaryJWTInterceptor(secret []byte) grpc.UnaryServerInterceptor {
 		}
 
 		ctx = context.WithValue(ctx, contextkeys.UserIDKey, userID)
+		if role, ok := claims["role"].(string); ok {
+			ctx = context.WithValue(ctx, contextkeys.RoleKey, role)
+		}
 		return handler(ctx, req)
 
 	}
 }
 
-func New(log *jsonlog.Logger, port int, subService subgrpc.Subscription) *App {
+func New(log *jsonlog.Logger, port int, subService subgrpc.Subscription, planAdmin subgrpc.Plans, idemStore IdempotencyStore, idemRetention, idemLease time.Duration) *App {
 	gRPCServer := grpc.NewServer(
-		grpc.UnaryInterceptor(UnaryJWTInterceptor([]byte("test-secret"))),
+		grpc.ChainUnaryInterceptor(
+			UnaryJWTInterceptor([]byte("test-secret")),
+			UnaryIdempotencyInterceptor(log, idemStore, idemRetention, idemLease),
+		),
 	)
 
-	subgrpc.Register(gRPCServer, subService)
+	subgrpc.Register(gRPCServer, subService, planAdmin)
 
 	return &App{
 		Log:        log,
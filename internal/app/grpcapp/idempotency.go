@@ -0,0 +1,287 @@
+package grpcapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	"subscriptionMService/internal/app/workerapp"
+	"subscriptionMService/internal/contextkeys"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the metadata key clients use to make a mutating call
+// safe to retry.
+const IdempotencyKeyHeader = "idempotency-key"
+
+// idempotentMethods are the RPCs whose responses are stored and replayed.
+var idempotentMethods = map[string]bool{
+	subs.Subscription_Subscribe_FullMethodName:          true,
+	subs.Subscription_ChangeSubsPlan_FullMethodName:     true,
+	subs.Subscription_Unsubscribe_FullMethodName:        true,
+	subs.Subscription_ExtractFromBalance_FullMethodName: true,
+	subs.Subscription_AddToBalance_FullMethodName:       true,
+}
+
+// requestMetadataKeys are the metadata keys that carry request parameters the
+// proto messages have no fields for. They are part of the request hash, so a
+// retry with a different coupon or reason is treated as a different payload.
+var requestMetadataKeys = []string{
+	"cancel-reason",
+	"balance-reason",
+	"balance-reference",
+	"change-at",
+	"coupon-code",
+	"currency",
+}
+
+type IdempotencyStore interface {
+	ClaimIdempotencyKey(ctx context.Context, userId int64, key, method string, requestHash []byte, retention, lease time.Duration) (*data.IdempotencyRecord, bool, error)
+	RenewIdempotencyKey(ctx context.Context, userId int64, key string, lease time.Duration) error
+	SaveIdempotentResponse(ctx context.Context, userId int64, key string, response []byte, header map[string][]string) error
+	ReleaseIdempotencyKey(ctx context.Context, userId int64, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
+}
+
+// UnaryIdempotencyInterceptor replays the stored response and response header
+// when a mutating RPC is repeated with the same idempotency key within
+// retention. Only successful responses are stored; after a failure the key is
+// released so the client can retry with it. Both happen even if the caller
+// has gone away meanwhile. A key whose request is still running fails with
+// codes.Aborted; the lease on it is renewed while the handler runs, so it only
+// lapses if the server stops mid-request. Reusing a key for a different
+// request fails with codes.AlreadyExists. It must run after
+// UnaryJWTInterceptor, since keys are scoped to the user.
+func UnaryIdempotencyInterceptor(log *jsonlog.Logger, store IdempotencyStore, retention, lease time.Duration) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		if !idempotentMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		keys := md.Get(IdempotencyKeyHeader)
+		if len(keys) == 0 || keys[0] == "" {
+			return handler(ctx, req)
+		}
+		key := keys[0]
+		if len(key) > 255 {
+			return nil, status.Error(codes.InvalidArgument, "idempotency key must not be more than 255 bytes long")
+		}
+
+		userID, ok := ctx.Value(contextkeys.UserIDKey).(int64)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "user id is missing or invalid in context")
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		requestHash, err := hashRequest(info.FullMethod, msg, md)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Internal error")
+		}
+
+		record, claimed, err := store.ClaimIdempotencyKey(ctx, userID, key, info.FullMethod, requestHash, retention, lease)
+		if err != nil {
+			log.PrintError(err, map[string]string{
+				"method": "grpcapp.UnaryIdempotencyInterceptor",
+			})
+			return nil, status.Error(codes.Internal, "Internal error")
+		}
+
+		if !claimed {
+			if record.Method != info.FullMethod || !bytes.Equal(record.RequestHash, requestHash) {
+				return nil, status.Error(codes.AlreadyExists, "idempotency key was already used for a different request")
+			}
+			if record.Response == nil {
+				return nil, status.Error(codes.Aborted, "request with this idempotency key is still in progress")
+			}
+			if len(record.ResponseHeader) > 0 {
+				grpc.SetHeader(ctx, metadata.MD(record.ResponseHeader))
+			}
+			return replayResponse(record.Response)
+		}
+
+		recorder := &headerRecorder{}
+		if stream := grpc.ServerTransportStreamFromContext(ctx); stream != nil {
+			recorder.ServerTransportStream = stream
+			ctx = grpc.NewContextWithServerTransportStream(ctx, recorder)
+		}
+
+		stopRenewing := keepLease(context.WithoutCancel(ctx), log, store, userID, key, lease)
+		resp, err := handler(ctx, req)
+		stopRenewing()
+
+		storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 3*time.Second)
+		defer cancel()
+
+		if err != nil || !succeeded(resp) {
+			if releaseErr := store.ReleaseIdempotencyKey(storeCtx, userID, key); releaseErr != nil {
+				log.PrintError(releaseErr, map[string]string{
+					"method": "grpcapp.UnaryIdempotencyInterceptor",
+				})
+			}
+			return resp, err
+		}
+
+		stored, err := storeResponse(resp)
+		if err == nil {
+			err = store.SaveIdempotentResponse(storeCtx, userID, key, stored, recorder.header)
+		}
+		if err != nil {
+			log.PrintError(err, map[string]string{
+				"method": "grpcapp.UnaryIdempotencyInterceptor",
+				"key":    key,
+			})
+		}
+		return resp, nil
+	}
+}
+
+// keepLease renews the lease on key every third of lease until the returned
+// function is called, so that a slow request is not taken for an abandoned
+// one and run a second time.
+func keepLease(ctx context.Context, log *jsonlog.Logger, store IdempotencyStore, userId int64, key string, lease time.Duration) func() {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(max(lease/3, time.Second))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := store.RenewIdempotencyKey(ctx, userId, key, lease); err != nil {
+					log.PrintError(err, map[string]string{
+						"method": "grpcapp.keepLease",
+						"key":    key,
+					})
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// PurgeIdempotencyKeys returns a job that removes keys older than retention.
+func PurgeIdempotencyKeys(log *jsonlog.Logger, store IdempotencyStore, retention time.Duration) workerapp.Job {
+	return func(ctx context.Context) {
+		deleted, err := store.DeleteExpiredIdempotencyKeys(ctx, time.Now().Add(-retention))
+		if err != nil {
+			if ctx.Err() == nil {
+				log.PrintError(err, map[string]string{
+					"method": "grpcapp.PurgeIdempotencyKeys",
+				})
+			}
+			return
+		}
+		if deleted > 0 {
+			log.PrintInfo("purged expired idempotency keys", map[string]string{
+				"deleted": fmt.Sprint(deleted),
+			})
+		}
+	}
+}
+
+func hashRequest(method string, req proto.Message, md metadata.MD) ([]byte, error) {
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte(method + "\n"))
+	h.Write(payload)
+	for _, key := range requestMetadataKeys {
+		fmt.Fprintf(h, "\n%s=%q", key, md.Get(key))
+	}
+	return h.Sum(nil), nil
+}
+
+// headerRecorder passes response header metadata on to the real stream while
+// keeping a copy, so that it can be stored with the response.
+type headerRecorder struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (r *headerRecorder) SetHeader(md metadata.MD) error {
+	if err := r.ServerTransportStream.SetHeader(md); err != nil {
+		return err
+	}
+	r.header = metadata.Join(r.header, md)
+	return nil
+}
+
+func (r *headerRecorder) SendHeader(md metadata.MD) error {
+	if err := r.ServerTransportStream.SendHeader(md); err != nil {
+		return err
+	}
+	r.header = metadata.Join(r.header, md)
+	return nil
+}
+
+// succeeded reports whether resp carries no failure status. Some handlers
+// report failures in the Status fields of the subsProto responses instead of
+// returning an error, and those must not be replayed.
+func succeeded(resp interface{}) bool {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return false
+	}
+	statusEnum := subs.Status_STATUS_OK.Descriptor().FullName()
+	ok = true
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() == protoreflect.EnumKind && fd.Enum().FullName() == statusEnum && subs.Status(v.Enum()) != subs.Status_STATUS_OK {
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
+func storeResponse(resp interface{}) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("response %T is not a proto message", resp)
+	}
+	wrapped, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(wrapped)
+}
+
+func replayResponse(stored []byte) (interface{}, error) {
+	var wrapped anypb.Any
+	if err := proto.Unmarshal(stored, &wrapped); err != nil {
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	resp, err := wrapped.UnmarshalNew()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	return resp, nil
+}
@@ -0,0 +1,244 @@
+package grpcapp
+
+import (
+	"context"
+	"errors"
+	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"subscriptionMService/internal/contextkeys"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryIdempotencyStore keeps claimed keys in memory. Like the database it
+// refuses to work on a cancelled context.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*data.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: map[string]*data.IdempotencyRecord{}}
+}
+
+func (s *memoryIdempotencyStore) ClaimIdempotencyKey(ctx context.Context, userId int64, key, method string, requestHash []byte, retention, lease time.Duration) (*data.IdempotencyRecord, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[key]; ok {
+		return record, false, nil
+	}
+	s.records[key] = &data.IdempotencyRecord{UserID: userId, Key: key, Method: method, RequestHash: requestHash}
+	return nil, true, nil
+}
+
+func (s *memoryIdempotencyStore) RenewIdempotencyKey(ctx context.Context, userId int64, key string, lease time.Duration) error {
+	return ctx.Err()
+}
+
+func (s *memoryIdempotencyStore) SaveIdempotentResponse(ctx context.Context, userId int64, key string, response []byte, header map[string][]string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key].Response = response
+	s.records[key].ResponseHeader = header
+	return nil
+}
+
+func (s *memoryIdempotencyStore) ReleaseIdempotencyKey(ctx context.Context, userId int64, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record, ok := s.records[key]; ok && record.Response == nil {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestUnaryIdempotencyInterceptor(t *testing.T) {
+	method := subs.Subscription_AddToBalance_FullMethodName
+	req := &subs.AddToBalanceRequest{Value: 3}
+	md := metadata.Pairs(IdempotencyKeyHeader, "key-1")
+
+	requestHash, err := hashRequest(method, req, md)
+	if err != nil {
+		t.Fatalf("hashRequest() = %v", err)
+	}
+	stored, err := storeResponse(&subs.AddToBalanceResponse{OpStatus: subs.Status_STATUS_OK, Left: 7})
+	if err != nil {
+		t.Fatalf("storeResponse() = %v", err)
+	}
+
+	ok := func(ctx context.Context, cancel context.CancelFunc) (interface{}, error) {
+		return &subs.AddToBalanceResponse{OpStatus: subs.Status_STATUS_OK, Left: 10}, nil
+	}
+
+	tests := []struct {
+		name         string
+		existing     *data.IdempotencyRecord
+		handler      func(ctx context.Context, cancel context.CancelFunc) (interface{}, error)
+		wantCode     codes.Code
+		wantLeft     int64
+		wantCalls    int
+		wantReleased bool
+	}{
+		{
+			name:      "stores a successful response",
+			handler:   ok,
+			wantLeft:  10,
+			wantCalls: 1,
+		},
+		{
+			name:     "replays a stored response",
+			existing: &data.IdempotencyRecord{Method: method, RequestHash: requestHash, Response: stored},
+			handler:  ok,
+			wantLeft: 7,
+		},
+		{
+			name:     "conflicts with a request in flight",
+			existing: &data.IdempotencyRecord{Method: method, RequestHash: requestHash},
+			handler:  ok,
+			wantCode: codes.Aborted,
+		},
+		{
+			name:     "rejects a different request under the key",
+			existing: &data.IdempotencyRecord{Method: method, RequestHash: []byte("other"), Response: stored},
+			handler:  ok,
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name: "releases the key after a handler error",
+			handler: func(ctx context.Context, cancel context.CancelFunc) (interface{}, error) {
+				return nil, status.Error(codes.Internal, "Internal error")
+			},
+			wantCode:     codes.Internal,
+			wantCalls:    1,
+			wantReleased: true,
+		},
+		{
+			name: "releases the key after a failure status",
+			handler: func(ctx context.Context, cancel context.CancelFunc) (interface{}, error) {
+				return &subs.AddToBalanceResponse{OpStatus: subs.Status_STATUS_INTERNAL_ERROR}, nil
+			},
+			wantCalls:    1,
+			wantReleased: true,
+		},
+		{
+			name: "stores the response after the caller gave up",
+			handler: func(ctx context.Context, cancel context.CancelFunc) (interface{}, error) {
+				cancel()
+				return &subs.AddToBalanceResponse{OpStatus: subs.Status_STATUS_OK, Left: 10}, nil
+			},
+			wantLeft:  10,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryIdempotencyStore()
+			if tt.existing != nil {
+				store.records["key-1"] = tt.existing
+			}
+			interceptor := UnaryIdempotencyInterceptor(jsonlog.New(io.Discard, jsonlog.LevelOff), store, 24*time.Hour, time.Minute)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctx = context.WithValue(metadata.NewIncomingContext(ctx, md), contextkeys.UserIDKey, int64(1))
+
+			calls := 0
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				calls++
+				return tt.handler(ctx, cancel)
+			}
+
+			resp, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("interceptor() error = %v, want code %s", err, tt.wantCode)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+			if err == nil {
+				if got := resp.(*subs.AddToBalanceResponse).GetLeft(); got != tt.wantLeft {
+					t.Errorf("response left = %d, want %d", got, tt.wantLeft)
+				}
+			}
+
+			record, held := store.records["key-1"]
+			if held == tt.wantReleased {
+				t.Errorf("key held = %t, want %t", held, !tt.wantReleased)
+			}
+			if tt.wantCalls > 0 && !tt.wantReleased && (!held || record.Response == nil) {
+				t.Error("response was not stored")
+			}
+		})
+	}
+}
+
+func TestUnaryIdempotencyInterceptorRetryReplays(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	interceptor := UnaryIdempotencyInterceptor(jsonlog.New(io.Discard, jsonlog.LevelOff), store, 24*time.Hour, time.Minute)
+	info := &grpc.UnaryServerInfo{FullMethod: subs.Subscription_AddToBalance_FullMethodName}
+
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return &subs.AddToBalanceResponse{OpStatus: subs.Status_STATUS_OK, Left: int64(10 - calls)}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyHeader, "key-1"))
+		ctx = context.WithValue(ctx, contextkeys.UserIDKey, int64(1))
+
+		resp, err := interceptor(ctx, &subs.AddToBalanceRequest{Value: 1}, info, handler)
+		if err != nil {
+			t.Fatalf("call %d: interceptor() = %v", i+1, err)
+		}
+		if got := resp.(*subs.AddToBalanceResponse).GetLeft(); got != 9 {
+			t.Errorf("call %d: left = %d, want 9", i+1, got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestUnaryIdempotencyInterceptorWithoutKey(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	interceptor := UnaryIdempotencyInterceptor(jsonlog.New(io.Discard, jsonlog.LevelOff), store, 24*time.Hour, time.Minute)
+	info := &grpc.UnaryServerInfo{FullMethod: subs.Subscription_AddToBalance_FullMethodName}
+
+	errHandler := errors.New("handler failed")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errHandler
+	}
+
+	ctx := context.WithValue(context.Background(), contextkeys.UserIDKey, int64(1))
+	if _, err := interceptor(ctx, &subs.AddToBalanceRequest{Value: 1}, info, handler); !errors.Is(err, errHandler) {
+		t.Errorf("interceptor() = %v, want %v", err, errHandler)
+	}
+	if len(store.records) != 0 {
+		t.Errorf("stored %d keys, want none", len(store.records))
+	}
+}
@@ -0,0 +1,72 @@
+package workerapp
+
+import (
+	"context"
+	"subscriptionMService/internal/jsonlog"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work executed on every tick. It should return
+// promptly once ctx is cancelled.
+type Job func(ctx context.Context)
+
+type App struct {
+	Log      *jsonlog.Logger
+	Name     string
+	Interval time.Duration
+	job      Job
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func New(log *jsonlog.Logger, name string, interval time.Duration, job Job) *App {
+	return &App{
+		Log:      log,
+		Name:     name,
+		Interval: interval,
+		job:      job,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run blocks and executes the job every interval until Stop is called.
+func (a *App) Run() {
+	defer close(a.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-a.stop
+		cancel()
+	}()
+
+	a.Log.PrintInfo("Running background worker", map[string]string{
+		"worker":   a.Name,
+		"interval": a.Interval.String(),
+	})
+
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+
+	for {
+		a.job(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop signals Run to return and waits for the job in progress to finish.
+func (a *App) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.stop)
+	})
+	<-a.done
+}
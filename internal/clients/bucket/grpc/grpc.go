@@ -50,7 +50,7 @@ func New(ctx context.Context, log *jsonlog.Logger, timeout time.Duration, retrie
 func InterceptorLogger(logger *jsonlog.Logger) grpclog.Logger {
 	return grpclog.LoggerFunc(func(ctx context.Context, lvl grpclog.Level, msg string, fields ...any) {
 		logger.PrintInfo(msg, map[string]string{
-			"lvl": string(lvl),
+			"lvl": fmt.Sprint(lvl),
 		})
 	},
 	)
@@ -92,6 +92,10 @@ func (b *BucketClient) CreateBucket(ctx context.Context) *bckt.CreateBucketRespo
 			"method":  "bucket.grpc.createBucket",
 			"service": "bucket",
 		})
+		return &bckt.CreateBucketResponse{
+			Status: bckt.OperationStatus_STATUS_INTERNAL_ERROR,
+			Msg:    err.Error(),
+		}
 	}
 	return reps
 }
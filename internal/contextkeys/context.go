@@ -1,5 +1,31 @@
 package contextkeys
 
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
 type ContentKey string
 
-const UserIDKey = ContentKey("user_id")
+const (
+	UserIDKey = ContentKey("user_id")
+	RoleKey   = ContentKey("role")
+)
+
+// AdminRole is the JWT role claim required by admin operations.
+const AdminRole = "admin"
+
+// IsAdmin reports whether the caller's token carries the admin role.
+func IsAdmin(ctx context.Context) bool {
+	role, _ := ctx.Value(RoleKey).(string)
+	return role == AdminRole
+}
+
+// RequireAdmin returns a PermissionDenied error unless the caller is an admin.
+func RequireAdmin(ctx context.Context) error {
+	if !IsAdmin(ctx) {
+		return status.Error(codes.PermissionDenied, "admin role required")
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package data
+
+import (
+	"regexp"
+	"subscriptionMService/internal/validator"
+)
+
+// Supported notification locales.
+const (
+	LocaleRU = "ru"
+	LocaleEN = "en"
+)
+
+var EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+// Contact is where and how a user wants to be notified.
+type Contact struct {
+	UserID               int64
+	Email                string
+	Locale               string
+	NotificationsEnabled bool
+}
+
+func ValidateContact(v *validator.Validator, c *Contact) {
+	v.Check(c.Email != "", "email", "must be provided")
+	v.Check(len(c.Email) <= 254, "email", "must not be more than 254 bytes long")
+	v.Check(EmailRX.MatchString(c.Email), "email", "must be a valid email address")
+	v.Check(c.Locale == LocaleRU || c.Locale == LocaleEN, "locale", "must be ru or en")
+}
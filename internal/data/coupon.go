@@ -0,0 +1,82 @@
+package data
+
+import (
+	"subscriptionMService/internal/money"
+	"subscriptionMService/internal/validator"
+	"time"
+)
+
+// Coupon discount types. Percent discounts take DiscountValue percent off the
+// plan price; fixed ones take DiscountValue off it, never going below zero.
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// Coupon is a promo code redeemable during Subscribe or ChangeSubsPlan. A zero
+// MaxRedemptions means unlimited, zero ValidFrom/ValidUntil leave the window
+// open on that side, and an empty PlanIDs allows every plan. Fixed discounts
+// are in minor units of Currency and only apply to subscriptions in it.
+type Coupon struct {
+	ID             int64
+	Code           string
+	DiscountType   string
+	DiscountValue  int64
+	Currency       string
+	BonusLimit     int32
+	MaxRedemptions int32
+	PerUserLimit   int32
+	ValidFrom      time.Time
+	ValidUntil     time.Time
+	PlanIDs        []int32
+	DisabledAt     time.Time
+	CreatedAt      time.Time
+}
+
+type CouponRedemption struct {
+	ID             int64
+	CouponID       int64
+	UserID         int64
+	SubscriptionID int64
+	PlanID         int32
+	DiscountAmount int64
+	BonusLimit     int32
+	RedeemedAt     time.Time
+}
+
+// CouponReport summarises the redemptions of one coupon.
+type CouponReport struct {
+	CouponID        int64
+	Code            string
+	Disabled        bool
+	Redemptions     int
+	Users           int
+	TotalDiscount   int64
+	TotalBonusLimit int64
+}
+
+func ValidateCoupon(v *validator.Validator, c *Coupon) {
+	v.Check(c.Code != "", "code", "must be provided")
+	v.Check(len(c.Code) <= 64, "code", "must not be more than 64 bytes long")
+	v.Check(c.DiscountType == DiscountPercent || c.DiscountType == DiscountFixed, "discount_type", "must be percent or fixed")
+	v.Check(c.DiscountValue >= 0, "discount_value", "must not be negative")
+	v.Check(c.DiscountType != DiscountPercent || c.DiscountValue <= 100, "discount_value", "must be a maximum of 100 percent")
+	v.Check(c.DiscountType != DiscountFixed || money.Supported(c.Currency), "currency", "must be a supported currency code")
+	v.Check(c.BonusLimit >= 0, "bonus_limit", "must not be negative")
+	v.Check(c.DiscountValue > 0 || c.BonusLimit > 0, "discount_value", "coupon must give a discount or a bonus limit")
+	v.Check(c.MaxRedemptions >= 0, "max_redemptions", "must not be negative")
+	v.Check(c.PerUserLimit > 0, "per_user_limit", "must be greater than zero")
+	v.Check(c.ValidUntil.IsZero() || c.ValidFrom.IsZero() || c.ValidUntil.After(c.ValidFrom), "valid_until", "must be after valid_from")
+}
+
+// Discount returns how much the coupon takes off price.
+func (c Coupon) Discount(price int64) int64 {
+	var discount int64
+	switch c.DiscountType {
+	case DiscountPercent:
+		discount = price * c.DiscountValue / 100
+	case DiscountFixed:
+		discount = c.DiscountValue
+	}
+	return min(discount, price)
+}
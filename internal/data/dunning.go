@@ -0,0 +1,67 @@
+package data
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dunning attempt statuses. A subscription whose renewal charge still fails
+// when its grace period ends opens a dunning cycle, and each retry in it is
+// scheduled ahead as its own attempt.
+// The exhausted attempt records that the schedule ran out and the
+// subscription expired.
+const (
+	DunningScheduled = "scheduled"
+	DunningSucceeded = "succeeded"
+	DunningFailed    = "failed"
+	DunningCancelled = "cancelled"
+	DunningExhausted = "exhausted"
+)
+
+// DunningRecovered is the detail recorded with the renewal that ends a
+// dunning cycle, and DunningGaveUp the one recorded with its expiry.
+const (
+	DunningRecovered = "payment recovered"
+	DunningGaveUp    = "payment retries exhausted"
+)
+
+// DunningAttempt is one charge of a past due subscription. Attempt 0 is the
+// last renewal charge that failed during grace; later attempts are the
+// scheduled retries.
+// PastDueSince identifies the cycle the attempt belongs to.
+type DunningAttempt struct {
+	ID              int64
+	SubscriptionID  int64
+	UserID          int64
+	PastDueSince    time.Time
+	Attempt         int32
+	Status          string
+	ScheduledAt     time.Time
+	AttemptedAt     time.Time
+	PaymentIntentID int64
+	FailureReason   string
+}
+
+// ParseDunningSchedule parses a comma separated list of whole days after the
+// paid period ended on which a past due charge is retried, such as "1,3,7". The days must
+// be increasing. An empty list disables retries.
+func ParseDunningSchedule(s string) ([]time.Duration, error) {
+	var schedule []time.Duration
+	if strings.TrimSpace(s) == "" {
+		return schedule, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		days, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || days <= 0 {
+			return nil, errors.New("dunning schedule must be a list of positive days")
+		}
+		d := time.Duration(days) * 24 * time.Hour
+		if len(schedule) > 0 && d <= schedule[len(schedule)-1] {
+			return nil, errors.New("dunning schedule days must be increasing")
+		}
+		schedule = append(schedule, d)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,61 @@
+package data
+
+import "time"
+
+// Event types recorded in subscription_events.
+const (
+	EventSubscribed          = "subscribed"
+	EventReactivated         = "reactivated"
+	EventPlanChanged         = "plan_changed"
+	EventPlanChangeScheduled = "plan_change_scheduled"
+	EventPlanChangeCancelled = "plan_change_cancelled"
+	EventPlanMigrated        = "plan_migrated"
+	EventCancelled           = "cancelled"
+	EventExpired             = "expired"
+	EventRenewed             = "renewed"
+	EventRenewalFailed       = "renewal_failed"
+	EventTrialConverted      = "trial_converted"
+	EventPaused              = "paused"
+	EventResumed             = "resumed"
+	EventBalanceDebited      = "balance_debited"
+	EventBalanceCredited     = "balance_credited"
+	EventCouponRedeemed      = "coupon_redeemed"
+	EventRetentionAccepted   = "retention_accepted"
+	EventActivated           = "activated"
+	EventProvisioningFailed  = "provisioning_failed"
+	EventPaymentDisputed     = "payment_disputed"
+	EventRefunded            = "refunded"
+	EventReminder            = "reminder"
+	EventPastDue             = "past_due"
+	EventDunningScheduled    = "dunning_scheduled"
+)
+
+// SubEvent is an append-only record of a change to a subscription, holding a
+// snapshot of the row as it was right after the change.
+type SubEvent struct {
+	ID             int64
+	SubscriptionID int64
+	UserID         int64
+	Type           string
+	PlanID         int32
+	Status         string
+	RemainingLimit int32
+	ExpiresAt      time.Time
+	Amount         int64
+	Details        string
+	CreatedAt      time.Time
+}
+
+// Reminder kinds, recorded in the details of EventReminder events.
+const (
+	ReminderRenewalUpcoming = "renewal_upcoming"
+	ReminderExpiring        = "expiring"
+	ReminderLowLimit        = "low_limit"
+)
+
+// Reminder is a notice queued for a subscription once per kind per period.
+type Reminder struct {
+	SubscriptionID  int64
+	Kind            string
+	PeriodStartedAt time.Time
+}
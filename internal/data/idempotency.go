@@ -0,0 +1,16 @@
+package data
+
+import "time"
+
+// IdempotencyRecord is a stored idempotency key. Response is nil while the
+// original request is still being processed. ResponseHeader holds the
+// response metadata the handler set, replayed along with the response.
+type IdempotencyRecord struct {
+	UserID         int64
+	Key            string
+	Method         string
+	RequestHash    []byte
+	Response       []byte
+	ResponseHeader map[string][]string
+	CreatedAt      time.Time
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"fmt"
+	"subscriptionMService/internal/money"
+	"time"
+)
+
+// Invoice kinds. Subscribe, renewal and proration invoices are issued when
+// their payment is applied. Proration invoices issued open before upgrades
+// were charged up front are paid by the next renewal. A credit note refunds
+// part of an earlier invoice and points back at it.
+const (
+	InvoiceSubscribe  = "subscribe"
+	InvoiceRenewal    = "renewal"
+	InvoiceProration  = "proration"
+	InvoiceCreditNote = "credit_note"
+)
+
+// Number series. Credit notes are numbered apart from invoices.
+const (
+	SeriesInvoice    = "INV"
+	SeriesCreditNote = "CN"
+)
+
+// Invoice statuses. An open invoice is paid or voided; a paid one can then be
+// refunded in part or in full.
+const (
+	InvoiceOpen              = "open"
+	InvoicePaid              = "paid"
+	InvoicePartiallyRefunded = "partially_refunded"
+	InvoiceRefunded          = "refunded"
+	InvoiceVoid              = "void"
+)
+
+var invoiceTransitions = map[string][]string{
+	InvoiceOpen:              {InvoicePaid, InvoiceVoid},
+	InvoicePaid:              {InvoicePartiallyRefunded, InvoiceRefunded},
+	InvoicePartiallyRefunded: {InvoicePartiallyRefunded, InvoiceRefunded},
+}
+
+// CanTransitionInvoice reports whether an invoice may move from one status to
+// another.
+func CanTransitionInvoice(from, to string) bool {
+	for _, next := range invoiceTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Invoice is a numbered record of what was charged, or credited back, for a
+// subscription period. Amounts are in minor units of Currency. Prices include
+// tax: Total is the sum of the lines, TaxAmount the part of it that is tax at
+// TaxRate basis points, and Subtotal what is left.
+type Invoice struct {
+	ID                int64
+	Number            string
+	SubscriptionID    int64
+	UserID            int64
+	PaymentIntentID   int64
+	OriginalInvoiceID int64
+	Kind              string
+	Status            string
+	Currency          string
+	Subtotal          int64
+	TaxName           string
+	TaxRate           int32
+	TaxAmount         int64
+	Total             int64
+	PeriodStart       time.Time
+	PeriodEnd         time.Time
+	IssuedAt          time.Time
+	PaidAt            time.Time
+	Lines             []InvoiceLine
+}
+
+// InvoiceLine is one item on an invoice. Discounts are lines with a negative
+// Amount and no plan.
+type InvoiceLine struct {
+	ID          int64
+	PlanID      int32
+	Description string
+	Quantity    int32
+	UnitAmount  int64
+	Amount      int64
+}
+
+// InvoiceNumber formats the seq-th document of series issued in year, for
+// example INV-2025-000042.
+func InvoiceNumber(series string, year, seq int) string {
+	return fmt.Sprintf("%s-%d-%06d", series, year, seq)
+}
+
+// Series returns the number series of the invoice's kind.
+func (i *Invoice) Series() string {
+	if i.Kind == InvoiceCreditNote {
+		return SeriesCreditNote
+	}
+	return SeriesInvoice
+}
+
+// IncludedTax returns the tax contained in a tax-inclusive total at rate basis
+// points.
+func IncludedTax(total int64, rate int32) int64 {
+	return money.Round(float64(total) * float64(rate) / float64(10000+rate))
+}
@@ -0,0 +1,34 @@
+package data
+
+import "time"
+
+// Counter accounts a subscription balance is moved against. Every ledger entry
+// moves rental limit between the user's balance and one of these.
+const (
+	AccountRentals        = "rentals"
+	AccountPlanAllowance  = "plan_allowance"
+	AccountOpeningBalance = "opening_balance"
+	AccountPromotions     = "promotions"
+)
+
+type BalanceTransaction struct {
+	ID             int64
+	SubscriptionID int64
+	UserID         int64
+	EntryType      string
+	Amount         int64
+	CounterAccount string
+	Reason         string
+	Reference      string
+	BalanceAfter   int64
+	CreatedAt      time.Time
+}
+
+// BalanceDrift describes a subscription whose stored remaining_limit does not
+// match the sum of its ledger entries.
+type BalanceDrift struct {
+	SubscriptionID int64
+	UserID         int64
+	RemainingLimit int64
+	LedgerBalance  int64
+}
@@ -0,0 +1,18 @@
+package data
+
+import "time"
+
+// OutboxMessage is a subscription event waiting in the outbox to be
+// published. Payload is the JSON snapshot of the event; EventID is the id of
+// the subscription_events row and is stable across redeliveries, so consumers
+// can use it to drop duplicates.
+type OutboxMessage struct {
+	ID             int64
+	EventID        int64
+	SubscriptionID int64
+	UserID         int64
+	EventType      string
+	Payload        []byte
+	Attempts       int
+	CreatedAt      time.Time
+}
@@ -0,0 +1,64 @@
+package data
+
+import "time"
+
+// Payment intent purposes.
+const (
+	PaymentSubscribe = "subscribe"
+	PaymentRenewal   = "renewal"
+	PaymentProration = "proration"
+)
+
+// Payment intent statuses. An intent moves from created through authorized to
+// captured, or ends in failed or cancelled on the way. A captured intent can
+// be refunded in part, and is refunded once RefundedAmount reaches Amount. A
+// captured intent the payer has disputed with their bank becomes disputed.
+const (
+	PaymentCreated    = "created"
+	PaymentAuthorized = "authorized"
+	PaymentCaptured   = "captured"
+	PaymentFailed     = "failed"
+	PaymentCancelled  = "cancelled"
+	PaymentRefunded   = "refunded"
+	PaymentDisputed   = "disputed"
+)
+
+var paymentTransitions = map[string][]string{
+	PaymentCreated:    {PaymentAuthorized, PaymentCaptured, PaymentFailed, PaymentCancelled},
+	PaymentAuthorized: {PaymentCaptured, PaymentFailed, PaymentCancelled},
+	PaymentCaptured:   {PaymentRefunded, PaymentDisputed},
+}
+
+// CanTransitionPayment reports whether a payment intent may move from one
+// status to another. Created intents go straight to captured only when there
+// is nothing to charge.
+func CanTransitionPayment(from, to string) bool {
+	for _, next := range paymentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentIntent is one attempt to collect Amount, in minor units of Currency,
+// for a subscription. DiscountApplied is the coupon discount already taken
+// off Amount. AppliedAt is set once the outcome has been applied to the
+// subscription.
+type PaymentIntent struct {
+	ID              int64
+	SubscriptionID  int64
+	UserID          int64
+	PlanID          int32
+	Purpose         string
+	Amount          int64
+	DiscountApplied int64
+	Currency        string
+	Status          string
+	ProviderRef     string
+	FailureReason   string
+	RefundedAmount  int64
+	AppliedAt       time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -0,0 +1,38 @@
+package data
+
+import "testing"
+
+func TestCanTransitionPayment(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{PaymentCreated, PaymentAuthorized, true},
+		{PaymentCreated, PaymentCaptured, true},
+		{PaymentCreated, PaymentFailed, true},
+		{PaymentCreated, PaymentCancelled, true},
+		{PaymentCreated, PaymentRefunded, false},
+		{PaymentAuthorized, PaymentCaptured, true},
+		{PaymentAuthorized, PaymentFailed, true},
+		{PaymentAuthorized, PaymentCancelled, true},
+		{PaymentAuthorized, PaymentCreated, false},
+		{PaymentAuthorized, PaymentRefunded, false},
+		{PaymentCaptured, PaymentRefunded, true},
+		{PaymentCaptured, PaymentDisputed, true},
+		{PaymentCaptured, PaymentFailed, false},
+		{PaymentCaptured, PaymentAuthorized, false},
+		{PaymentFailed, PaymentAuthorized, false},
+		{PaymentFailed, PaymentCaptured, false},
+		{PaymentCancelled, PaymentAuthorized, false},
+		{PaymentRefunded, PaymentCaptured, false},
+		{PaymentDisputed, PaymentCaptured, false},
+		{PaymentCaptured, PaymentCaptured, false},
+		{"unknown", PaymentCaptured, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionPayment(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionPayment(%s, %s) = %t, want %t", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
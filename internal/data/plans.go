@@ -2,15 +2,98 @@ package data
 
 import (
 	"database/sql"
+	"subscriptionMService/internal/validator"
+	"time"
 )
 
+// Plan is one version of a subscription plan. Plans that subscribers are on
+// are never edited in place: a change creates a new version in the same
+// family and archives the old one, which takes no new subscribers. Existing
+// subscribers keep a grandfathered version for good; otherwise they move to
+// the family's current version at their next renewal.
 type Plan struct {
-	ID          int32
-	Name        string
-	Desc        string
-	RentalLimit int32
-	Price       int32
-	Duration    int32
+	ID                 int32
+	Name               string
+	Desc               string
+	RentalLimit        int32
+	Price              int32
+	Duration           int32
+	AutoRenew          bool
+	RenewalLeadHours   int32
+	GracePeriodDays    int32
+	MaxPauseDays       int32
+	TrialDays          int32
+	TrialRentalLimit   int32
+	LowLimitThreshold  int32
+	ExpiryReminderDays int32
+	FamilyID           int32
+	Version            int32
+	Grandfathered      bool
+	Visibility         string
+	AvailableFrom      time.Time
+	AvailableUntil     time.Time
+	SortOrder          int32
+	RefundPolicy       string
+	ArchivedAt         time.Time
+	CreatedAt          time.Time
+}
+
+// Refund policies, applied when a subscription on the plan is cancelled or
+// refunded by an admin. Partial refunds pay back the unused share of the
+// period; full refunds pay back the whole last payment. Either ends access as
+// soon as the refund is made. Under RefundNone a cancelled subscription keeps
+// access until the end of its period.
+const (
+	RefundNone    = "none"
+	RefundPartial = "partial"
+	RefundFull    = "full"
+)
+
+// Plan visibilities. Hidden plans are left out of ListPlans but can still be
+// bought by anyone who is given their id.
+const (
+	PlanPublic = "public"
+	PlanHidden = "hidden"
+)
+
+// Purchasable reports whether the plan can take new subscribers at now: it is
+// not archived and now falls inside its availability window. Zero window
+// bounds are open.
+func (p *Plan) Purchasable(now time.Time) bool {
+	if !p.ArchivedAt.IsZero() {
+		return false
+	}
+	if !p.AvailableFrom.IsZero() && now.Before(p.AvailableFrom) {
+		return false
+	}
+	if !p.AvailableUntil.IsZero() && !now.Before(p.AvailableUntil) {
+		return false
+	}
+	return true
+}
+
+func ValidatePlan(v *validator.Validator, p *Plan) {
+	v.Check(p.Name != "", "name", "must be provided")
+	v.Check(len(p.Name) <= 50, "name", "must not be more than 50 bytes long")
+	v.Check(p.Desc != "", "description", "must be provided")
+	v.Check(len(p.Desc) <= 500, "description", "must not be more than 500 bytes long")
+	v.Check(p.RentalLimit > 0, "rental_limit", "must be greater than zero")
+	v.Check(p.Price > 0, "price", "must be greater than zero")
+	v.Check(p.Duration > 0, "duration", "must be greater than zero")
+	v.Check(p.RenewalLeadHours >= 0, "renewal_lead_hours", "must not be negative")
+	v.Check(p.GracePeriodDays >= 0, "grace_period_days", "must not be negative")
+	v.Check(p.MaxPauseDays >= 0, "max_pause_days", "must not be negative")
+	v.Check(p.TrialDays >= 0, "trial_days", "must not be negative")
+	v.Check(p.TrialRentalLimit >= 0, "trial_rental_limit", "must not be negative")
+	v.Check(p.LowLimitThreshold >= 0, "low_limit_threshold", "must not be negative")
+	v.Check(p.ExpiryReminderDays >= 0, "expiry_reminder_days", "must not be negative")
+	ValidatePlanAvailability(v, p)
+	ValidateRefundPolicy(v, p.RefundPolicy)
+}
+
+func ValidatePlanAvailability(v *validator.Validator, p *Plan) {
+	v.Check(p.Visibility == PlanPublic || p.Visibility == PlanHidden, "visibility", "must be public or hidden")
+	v.Check(p.AvailableFrom.IsZero() || p.AvailableUntil.IsZero() || p.AvailableFrom.Before(p.AvailableUntil), "available_until", "must be after available_from")
 }
 
 type PlanModel struct {
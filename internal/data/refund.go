@@ -0,0 +1,70 @@
+package data
+
+import (
+	"slices"
+	"subscriptionMService/internal/validator"
+	"time"
+)
+
+// Refund reason codes, recorded with every refund.
+const (
+	RefundReasonCancellation    = "cancellation"
+	RefundReasonCustomerRequest = "customer_request"
+	RefundReasonServiceIssue    = "service_issue"
+	RefundReasonDuplicateCharge = "duplicate_charge"
+	RefundReasonFraud           = "fraud"
+	RefundReasonGoodwill        = "goodwill"
+)
+
+var refundReasons = []string{
+	RefundReasonCancellation,
+	RefundReasonCustomerRequest,
+	RefundReasonServiceIssue,
+	RefundReasonDuplicateCharge,
+	RefundReasonFraud,
+	RefundReasonGoodwill,
+}
+
+// RefundQuote is what a refund policy allows back of a subscription's last
+// payment. UnusedTime and UnusedLimit are the shares of the period and of
+// the plan's rental limit left, each in [0, 1]. Amount is in minor units of
+// the payment's currency.
+type RefundQuote struct {
+	Policy      string
+	Paid        int64
+	UnusedTime  float64
+	UnusedLimit float64
+	Amount      int64
+}
+
+// RefundAudit records who refunded what and why. ActorID is the admin who
+// issued it, or the user themselves for refunds made on cancellation.
+// PaidOutAt is zero until the refund has reached the payer.
+type RefundAudit struct {
+	ID              int64
+	SubscriptionID  int64
+	UserID          int64
+	ActorID         int64
+	ReasonCode      string
+	Note            string
+	PaymentIntentID int64
+	Currency        string
+	Quote           RefundQuote
+	CreatedAt       time.Time
+	PaidOutAt       time.Time
+}
+
+// RefundEndsAccess reports whether cancelling under policy pays money back,
+// and with it ends access straight away rather than at the end of the period.
+func RefundEndsAccess(policy string) bool {
+	return policy == RefundPartial || policy == RefundFull
+}
+
+func ValidateRefundPolicy(v *validator.Validator, policy string) {
+	v.Check(policy == RefundNone || policy == RefundPartial || policy == RefundFull, "refund_policy", "must be none, partial or full")
+}
+
+func ValidateRefundAudit(v *validator.Validator, a *RefundAudit) {
+	v.Check(slices.Contains(refundReasons, a.ReasonCode), "reason_code", "must be a known reason code")
+	v.Check(len(a.Note) <= 500, "note", "must not be more than 500 bytes long")
+}
@@ -0,0 +1,37 @@
+package data
+
+import "time"
+
+// Retention offer kinds.
+const (
+	RetentionDiscount   = "discount"
+	RetentionFreeMonth  = "free_month"
+	RetentionBonusLimit = "bonus_limit"
+)
+
+// Retention offer statuses.
+const (
+	RetentionOffered  = "offered"
+	RetentionAccepted = "accepted"
+	RetentionDeclined = "declined"
+)
+
+// RetentionOffer is an incentive shown to a user who is about to unsubscribe.
+// At most one is made per subscription per billing period. DiscountAmount is
+// taken off the next charge, BonusLimit is added to the balance and
+// FreeMonths extend the current period.
+type RetentionOffer struct {
+	ID              int64
+	SubscriptionID  int64
+	UserID          int64
+	PlanID          int32
+	Rule            string
+	Kind            string
+	DiscountAmount  int64
+	BonusLimit      int32
+	FreeMonths      int32
+	PeriodStartedAt time.Time
+	Status          string
+	OfferedAt       time.Time
+	RespondedAt     time.Time
+}
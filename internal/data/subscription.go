@@ -3,9 +3,47 @@ package data
 import "time"
 
 type Subscription struct {
+	ID                int64
+	UserID            int64
+	PlanID            int32
+	RemainingLimit    int32
+	ExpiresAt         time.Time
+	Status            string
+	PeriodStartedAt   time.Time
+	PendingPlanID     int32
+	PendingChangeAt   time.Time
+	OutstandingAmount int64
+	Currency          string
+	BucketProvisioned bool
+	TrialEndsAt       time.Time
+	CreatedAt         time.Time
+}
+
+type Renewal struct {
 	ID             int64
+	SubscriptionID int64
 	UserID         int64
 	PlanID         int32
-	RemainingLimit int32
+	Amount         int64
+	Status         string
+	Reason         string
 	ExpiresAt      time.Time
 }
+
+// Plan change kinds.
+const (
+	PlanChangeUpgrade   = "upgrade"
+	PlanChangeDowngrade = "downgrade"
+)
+
+// PlanChange is a quoted move to another plan. Immediate changes charge Amount
+// and then switch the plan, adding LimitTopUp to the balance; otherwise the
+// plan switches at EffectiveAt.
+type PlanChange struct {
+	PlanID      int32
+	Kind        string
+	Immediate   bool
+	Amount      int64
+	LimitTopUp  int32
+	EffectiveAt time.Time
+}
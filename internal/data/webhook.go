@@ -0,0 +1,18 @@
+package data
+
+import "time"
+
+// WebhookDelivery is a verified gateway webhook as it was received, kept so
+// that it is applied once and can be replayed. Payload is the raw request
+// body. ProcessedAt is set once the event has been applied; LastError holds
+// why the latest attempt failed.
+type WebhookDelivery struct {
+	ID          string
+	Type        string
+	ProviderRef string
+	Payload     []byte
+	Attempts    int32
+	LastError   string
+	ReceivedAt  time.Time
+	ProcessedAt time.Time
+}
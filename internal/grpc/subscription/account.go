@@ -0,0 +1,221 @@
+package subscription
+
+import (
+	"context"
+	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"subscriptionMService/gen/go/account"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// accountAPI serves the Account service, which carries the operations the
+// subsProto Subscription service has no RPCs for.
+type accountAPI struct {
+	account.UnimplementedAccountServer
+	subs Account
+}
+
+type Account interface {
+	PauseSubscription(ctx context.Context) subs.Status
+	ResumeSubscription(ctx context.Context) (subs.Status, string)
+	ListSubscriptionHistory(ctx context.Context, filters data.Filters) ([]*data.SubEvent, data.Metadata, error)
+	ListBalanceTransactions(ctx context.Context, filters data.Filters) ([]*data.BalanceTransaction, data.Metadata, error)
+	CancelScheduledPlanChange(ctx context.Context) subs.Status
+	PreviewUnsubscribe(ctx context.Context) (data.RetentionOffer, bool, subs.Status)
+	AcceptRetentionOffer(ctx context.Context, offerId int64) subs.Status
+	UpdateContact(ctx context.Context, email, locale string, enabled bool) error
+	ListInvoices(ctx context.Context, filters data.Filters) ([]*data.Invoice, data.Metadata, error)
+	GetInvoice(ctx context.Context, number string) (*data.Invoice, error)
+}
+
+func registerAccount(gRPC *grpc.Server, acc Account) {
+	account.RegisterAccountServer(gRPC, &accountAPI{subs: acc})
+}
+
+func (s *accountAPI) PauseSubscription(ctx context.Context, r *account.PauseRequest) (*account.PauseResponse, error) {
+	if opStatus := s.subs.PauseSubscription(ctx); opStatus != subs.Status_STATUS_OK {
+		return nil, mapStatusToError(opStatus)
+	}
+	return &account.PauseResponse{}, nil
+}
+
+func (s *accountAPI) ResumeSubscription(ctx context.Context, r *account.ResumeRequest) (*account.ResumeResponse, error) {
+	opStatus, expiresAt := s.subs.ResumeSubscription(ctx)
+	if opStatus != subs.Status_STATUS_OK {
+		return nil, mapStatusToError(opStatus)
+	}
+	return &account.ResumeResponse{ExpiresAt: expiresAt}, nil
+}
+
+func (s *accountAPI) ListSubscriptionHistory(ctx context.Context, r *account.HistoryRequest) (*account.HistoryResponse, error) {
+	events, metadata, err := s.subs.ListSubscriptionHistory(ctx, pageFilters(r.GetPage(), r.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &account.HistoryResponse{Metadata: toMetadata(metadata)}
+	for _, e := range events {
+		resp.Events = append(resp.Events, &account.SubEvent{
+			Id:             e.ID,
+			SubscriptionId: e.SubscriptionID,
+			Type:           e.Type,
+			PlanId:         e.PlanID,
+			Status:         e.Status,
+			RemainingLimit: e.RemainingLimit,
+			ExpiresAt:      e.ExpiresAt.Format(time.RFC3339),
+			Amount:         e.Amount,
+			Details:        e.Details,
+			CreatedAt:      e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+func (s *accountAPI) ListBalanceTransactions(ctx context.Context, r *account.TransactionsRequest) (*account.TransactionsResponse, error) {
+	transactions, metadata, err := s.subs.ListBalanceTransactions(ctx, pageFilters(r.GetPage(), r.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &account.TransactionsResponse{Metadata: toMetadata(metadata)}
+	for _, t := range transactions {
+		resp.Transactions = append(resp.Transactions, &account.BalanceTransaction{
+			Id:             t.ID,
+			SubscriptionId: t.SubscriptionID,
+			EntryType:      t.EntryType,
+			Amount:         t.Amount,
+			CounterAccount: t.CounterAccount,
+			Reason:         t.Reason,
+			Reference:      t.Reference,
+			BalanceAfter:   t.BalanceAfter,
+			CreatedAt:      t.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+func (s *accountAPI) CancelScheduledPlanChange(ctx context.Context, r *account.CancelPlanChangeRequest) (*account.CancelPlanChangeResponse, error) {
+	if opStatus := s.subs.CancelScheduledPlanChange(ctx); opStatus != subs.Status_STATUS_OK {
+		return nil, mapStatusToError(opStatus)
+	}
+	return &account.CancelPlanChangeResponse{}, nil
+}
+
+func (s *accountAPI) PreviewUnsubscribe(ctx context.Context, r *account.PreviewUnsubscribeRequest) (*account.PreviewUnsubscribeResponse, error) {
+	offer, ok, opStatus := s.subs.PreviewUnsubscribe(ctx)
+	if opStatus != subs.Status_STATUS_OK {
+		return nil, mapStatusToError(opStatus)
+	}
+	if !ok {
+		return &account.PreviewUnsubscribeResponse{}, nil
+	}
+	return &account.PreviewUnsubscribeResponse{
+		Offer: &account.RetentionOffer{
+			Id:             offer.ID,
+			PlanId:         offer.PlanID,
+			Kind:           offer.Kind,
+			DiscountAmount: offer.DiscountAmount,
+			BonusLimit:     offer.BonusLimit,
+			FreeMonths:     offer.FreeMonths,
+			OfferedAt:      offer.OfferedAt.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+func (s *accountAPI) AcceptRetentionOffer(ctx context.Context, r *account.AcceptOfferRequest) (*account.AcceptOfferResponse, error) {
+	if r.GetOfferId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "offer_id must be provided")
+	}
+	if opStatus := s.subs.AcceptRetentionOffer(ctx, r.GetOfferId()); opStatus != subs.Status_STATUS_OK {
+		return nil, mapStatusToError(opStatus)
+	}
+	return &account.AcceptOfferResponse{}, nil
+}
+
+func (s *accountAPI) UpdateContact(ctx context.Context, r *account.UpdateContactRequest) (*account.UpdateContactResponse, error) {
+	if err := s.subs.UpdateContact(ctx, r.GetEmail(), r.GetLocale(), r.GetNotificationsEnabled()); err != nil {
+		return nil, err
+	}
+	return &account.UpdateContactResponse{}, nil
+}
+
+func (s *accountAPI) ListInvoices(ctx context.Context, r *account.InvoicesRequest) (*account.InvoicesResponse, error) {
+	invoices, metadata, err := s.subs.ListInvoices(ctx, pageFilters(r.GetPage(), r.GetPageSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &account.InvoicesResponse{Metadata: toMetadata(metadata)}
+	for _, invoice := range invoices {
+		resp.Invoices = append(resp.Invoices, toInvoice(invoice))
+	}
+	return resp, nil
+}
+
+func (s *accountAPI) GetInvoice(ctx context.Context, r *account.GetInvoiceRequest) (*account.GetInvoiceResponse, error) {
+	if r.GetNumber() == "" {
+		return nil, status.Error(codes.InvalidArgument, "number must be provided")
+	}
+	invoice, err := s.subs.GetInvoice(ctx, r.GetNumber())
+	if err != nil {
+		return nil, err
+	}
+	return &account.GetInvoiceResponse{Invoice: toInvoice(invoice)}, nil
+}
+
+func toInvoice(i *data.Invoice) *account.Invoice {
+	invoice := &account.Invoice{
+		Number:      i.Number,
+		Kind:        i.Kind,
+		Status:      i.Status,
+		Currency:    i.Currency,
+		Subtotal:    i.Subtotal,
+		TaxName:     i.TaxName,
+		TaxRateBp:   i.TaxRate,
+		TaxAmount:   i.TaxAmount,
+		Total:       i.Total,
+		PeriodStart: i.PeriodStart.Format(time.RFC3339),
+		PeriodEnd:   i.PeriodEnd.Format(time.RFC3339),
+		IssuedAt:    i.IssuedAt.Format(time.RFC3339),
+		PaidAt:      formatTime(i.PaidAt),
+	}
+	for _, line := range i.Lines {
+		invoice.Lines = append(invoice.Lines, &account.InvoiceLine{
+			PlanId:      line.PlanID,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitAmount:  line.UnitAmount,
+			Amount:      line.Amount,
+		})
+	}
+	return invoice
+}
+
+// defaultPageSize is used for list calls that leave the page size unset.
+const defaultPageSize = 20
+
+// pageFilters builds the pagination filters of a list call, defaulting to the
+// first page of defaultPageSize.
+func pageFilters(page, pageSize int32) data.Filters {
+	filters := data.Filters{Page: int(page), PageSize: int(pageSize)}
+	if filters.Page == 0 {
+		filters.Page = 1
+	}
+	if filters.PageSize == 0 {
+		filters.PageSize = defaultPageSize
+	}
+	return filters
+}
+
+func toMetadata(m data.Metadata) *account.Metadata {
+	return &account.Metadata{
+		CurrentPage:  int32(m.CurrentPage),
+		PageSize:     int32(m.PageSize),
+		FirstPage:    int32(m.FirstPage),
+		LastPage:     int32(m.LastPage),
+		TotalRecords: int32(m.TotalRecords),
+	}
+}
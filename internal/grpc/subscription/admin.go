@@ -0,0 +1,345 @@
+package subscription
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"subscriptionMService/gen/go/admin"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/validator"
+	"time"
+)
+
+// adminAPI serves the Admin service. The services behind it check the
+// caller's admin role themselves.
+type adminAPI struct {
+	admin.UnimplementedAdminServer
+	subs  SubscriptionAdmin
+	plans Plans
+}
+
+type SubscriptionAdmin interface {
+	CreateCoupon(ctx context.Context, coupon *data.Coupon) error
+	DisableCoupon(ctx context.Context, code string) error
+	CouponReport(ctx context.Context) ([]*data.CouponReport, error)
+	TopUpWallet(ctx context.Context, userId int64, amount int64, currency string) (int64, error)
+	RefundSubscription(ctx context.Context, userId int64, reasonCode, note string) (*data.RefundAudit, error)
+}
+
+type Plans interface {
+	CreatePlan(ctx context.Context, plan *data.Plan) error
+	UpdatePlan(ctx context.Context, plan *data.Plan) (*data.Plan, error)
+	ArchivePlan(ctx context.Context, planId int32) error
+	GetPlan(ctx context.Context, planId int32) (*data.Plan, error)
+	SetGrandfathered(ctx context.Context, planId int32, grandfathered bool) error
+	SetAvailability(ctx context.Context, plan *data.Plan) (*data.Plan, error)
+	SetPrice(ctx context.Context, planId int32, currency string, amount int32) (*data.Plan, error)
+	SetRefundPolicy(ctx context.Context, planId int32, policy string) error
+}
+
+func registerAdmin(gRPC *grpc.Server, subscription SubscriptionAdmin, plans Plans) {
+	admin.RegisterAdminServer(gRPC, &adminAPI{subs: subscription, plans: plans})
+}
+
+func (s *adminAPI) CreateCoupon(ctx context.Context, r *admin.CreateCouponRequest) (*admin.CreateCouponResponse, error) {
+	v := validator.New()
+
+	c := r.GetCoupon()
+	validFrom := parseTime(v, "valid_from", c.GetValidFrom())
+	validUntil := parseTime(v, "valid_until", c.GetValidUntil())
+
+	if !v.Valid() {
+		return nil, collectErrors(v)
+	}
+
+	coupon := &data.Coupon{
+		Code:           c.GetCode(),
+		DiscountType:   c.GetDiscountType(),
+		DiscountValue:  c.GetDiscountValue(),
+		Currency:       c.GetCurrency(),
+		BonusLimit:     c.GetBonusLimit(),
+		MaxRedemptions: c.GetMaxRedemptions(),
+		PerUserLimit:   c.GetPerUserLimit(),
+		ValidFrom:      validFrom,
+		ValidUntil:     validUntil,
+		PlanIDs:        c.GetPlanIds(),
+	}
+	if err := s.subs.CreateCoupon(ctx, coupon); err != nil {
+		return nil, err
+	}
+	return &admin.CreateCouponResponse{Coupon: toCoupon(coupon)}, nil
+}
+
+func (s *adminAPI) DisableCoupon(ctx context.Context, r *admin.DisableCouponRequest) (*admin.DisableCouponResponse, error) {
+	if r.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code must be provided")
+	}
+	if err := s.subs.DisableCoupon(ctx, r.GetCode()); err != nil {
+		return nil, err
+	}
+	return &admin.DisableCouponResponse{}, nil
+}
+
+func (s *adminAPI) CouponReport(ctx context.Context, r *admin.CouponReportRequest) (*admin.CouponReportResponse, error) {
+	reports, err := s.subs.CouponReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &admin.CouponReportResponse{}
+	for _, report := range reports {
+		resp.Coupons = append(resp.Coupons, &admin.CouponUsage{
+			CouponId:        report.CouponID,
+			Code:            report.Code,
+			Disabled:        report.Disabled,
+			Redemptions:     int32(report.Redemptions),
+			Users:           int32(report.Users),
+			TotalDiscount:   report.TotalDiscount,
+			TotalBonusLimit: report.TotalBonusLimit,
+		})
+	}
+	return resp, nil
+}
+
+func (s *adminAPI) TopUpWallet(ctx context.Context, r *admin.TopUpWalletRequest) (*admin.TopUpWalletResponse, error) {
+	balance, err := s.subs.TopUpWallet(ctx, r.GetUserId(), r.GetAmount(), strings.ToUpper(r.GetCurrency()))
+	if err != nil {
+		return nil, err
+	}
+	return &admin.TopUpWalletResponse{Balance: balance}, nil
+}
+
+func (s *adminAPI) RefundSubscription(ctx context.Context, r *admin.RefundSubscriptionRequest) (*admin.RefundSubscriptionResponse, error) {
+	if r.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be provided")
+	}
+	audit, err := s.subs.RefundSubscription(ctx, r.GetUserId(), r.GetReasonCode(), r.GetNote())
+	if err != nil {
+		return nil, err
+	}
+	return &admin.RefundSubscriptionResponse{Refund: toRefundAudit(audit)}, nil
+}
+
+func (s *adminAPI) CreatePlan(ctx context.Context, r *admin.CreatePlanRequest) (*admin.CreatePlanResponse, error) {
+	plan, err := fromPlan(r.GetPlan())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.plans.CreatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	return &admin.CreatePlanResponse{Plan: toPlan(plan)}, nil
+}
+
+func (s *adminAPI) UpdatePlan(ctx context.Context, r *admin.UpdatePlanRequest) (*admin.UpdatePlanResponse, error) {
+	if r.GetPlan().GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "plan id must be provided")
+	}
+	plan, err := fromPlan(r.GetPlan())
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.plans.UpdatePlan(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	return &admin.UpdatePlanResponse{Plan: toPlan(updated)}, nil
+}
+
+func (s *adminAPI) ArchivePlan(ctx context.Context, r *admin.ArchivePlanRequest) (*admin.ArchivePlanResponse, error) {
+	if r.GetPlanId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "plan_id must be provided")
+	}
+	if err := s.plans.ArchivePlan(ctx, r.GetPlanId()); err != nil {
+		return nil, err
+	}
+	return &admin.ArchivePlanResponse{}, nil
+}
+
+func (s *adminAPI) GetPlan(ctx context.Context, r *admin.GetPlanRequest) (*admin.GetPlanResponse, error) {
+	if r.GetPlanId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "plan_id must be provided")
+	}
+	plan, err := s.plans.GetPlan(ctx, r.GetPlanId())
+	if err != nil {
+		return nil, err
+	}
+	return &admin.GetPlanResponse{Plan: toPlan(plan)}, nil
+}
+
+func (s *adminAPI) SetGrandfathered(ctx context.Context, r *admin.SetGrandfatheredRequest) (*admin.SetGrandfatheredResponse, error) {
+	if r.GetPlanId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "plan_id must be provided")
+	}
+	if err := s.plans.SetGrandfathered(ctx, r.GetPlanId(), r.GetGrandfathered()); err != nil {
+		return nil, err
+	}
+	return &admin.SetGrandfatheredResponse{}, nil
+}
+
+func (s *adminAPI) SetAvailability(ctx context.Context, r *admin.SetAvailabilityRequest) (*admin.SetAvailabilityResponse, error) {
+	v := validator.New()
+
+	v.Check(r.GetPlanId() != 0, "plan_id", "must be provided")
+	availableFrom := parseTime(v, "available_from", r.GetAvailableFrom())
+	availableUntil := parseTime(v, "available_until", r.GetAvailableUntil())
+
+	if !v.Valid() {
+		return nil, collectErrors(v)
+	}
+
+	updated, err := s.plans.SetAvailability(ctx, &data.Plan{
+		ID:             r.GetPlanId(),
+		Visibility:     r.GetVisibility(),
+		AvailableFrom:  availableFrom,
+		AvailableUntil: availableUntil,
+		SortOrder:      r.GetSortOrder(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &admin.SetAvailabilityResponse{Plan: toPlan(updated)}, nil
+}
+
+func (s *adminAPI) SetPrice(ctx context.Context, r *admin.SetPriceRequest) (*admin.SetPriceResponse, error) {
+	if r.GetPlanId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "plan_id must be provided")
+	}
+	updated, err := s.plans.SetPrice(ctx, r.GetPlanId(), strings.ToUpper(r.GetCurrency()), r.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	return &admin.SetPriceResponse{Plan: toPlan(updated)}, nil
+}
+
+func (s *adminAPI) SetRefundPolicy(ctx context.Context, r *admin.SetRefundPolicyRequest) (*admin.SetRefundPolicyResponse, error) {
+	if r.GetPlanId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "plan_id must be provided")
+	}
+	if err := s.plans.SetRefundPolicy(ctx, r.GetPlanId(), r.GetRefundPolicy()); err != nil {
+		return nil, err
+	}
+	return &admin.SetRefundPolicyResponse{}, nil
+}
+
+// fromPlan converts a plan in a request. The services validate everything but
+// the times, which are parsed here.
+func fromPlan(p *admin.Plan) (*data.Plan, error) {
+	v := validator.New()
+	availableFrom := parseTime(v, "available_from", p.GetAvailableFrom())
+	availableUntil := parseTime(v, "available_until", p.GetAvailableUntil())
+	if !v.Valid() {
+		return nil, collectErrors(v)
+	}
+
+	return &data.Plan{
+		ID:                 p.GetId(),
+		Name:               p.GetName(),
+		Desc:               p.GetDescription(),
+		RentalLimit:        p.GetRentalLimit(),
+		Price:              p.GetPrice(),
+		Duration:           p.GetDuration(),
+		AutoRenew:          p.GetAutoRenew(),
+		RenewalLeadHours:   p.GetRenewalLeadHours(),
+		GracePeriodDays:    p.GetGracePeriodDays(),
+		MaxPauseDays:       p.GetMaxPauseDays(),
+		TrialDays:          p.GetTrialDays(),
+		TrialRentalLimit:   p.GetTrialRentalLimit(),
+		LowLimitThreshold:  p.GetLowLimitThreshold(),
+		ExpiryReminderDays: p.GetExpiryReminderDays(),
+		Grandfathered:      p.GetGrandfathered(),
+		Visibility:         p.GetVisibility(),
+		AvailableFrom:      availableFrom,
+		AvailableUntil:     availableUntil,
+		SortOrder:          p.GetSortOrder(),
+		RefundPolicy:       p.GetRefundPolicy(),
+	}, nil
+}
+
+func toPlan(p *data.Plan) *admin.Plan {
+	return &admin.Plan{
+		Id:                 p.ID,
+		Name:               p.Name,
+		Description:        p.Desc,
+		RentalLimit:        p.RentalLimit,
+		Price:              p.Price,
+		Duration:           p.Duration,
+		AutoRenew:          p.AutoRenew,
+		RenewalLeadHours:   p.RenewalLeadHours,
+		GracePeriodDays:    p.GracePeriodDays,
+		MaxPauseDays:       p.MaxPauseDays,
+		TrialDays:          p.TrialDays,
+		TrialRentalLimit:   p.TrialRentalLimit,
+		LowLimitThreshold:  p.LowLimitThreshold,
+		ExpiryReminderDays: p.ExpiryReminderDays,
+		FamilyId:           p.FamilyID,
+		Version:            p.Version,
+		Grandfathered:      p.Grandfathered,
+		Visibility:         p.Visibility,
+		AvailableFrom:      formatTime(p.AvailableFrom),
+		AvailableUntil:     formatTime(p.AvailableUntil),
+		SortOrder:          p.SortOrder,
+		RefundPolicy:       p.RefundPolicy,
+		ArchivedAt:         formatTime(p.ArchivedAt),
+		CreatedAt:          formatTime(p.CreatedAt),
+	}
+}
+
+func toCoupon(c *data.Coupon) *admin.Coupon {
+	return &admin.Coupon{
+		Id:             c.ID,
+		Code:           c.Code,
+		DiscountType:   c.DiscountType,
+		DiscountValue:  c.DiscountValue,
+		Currency:       c.Currency,
+		BonusLimit:     c.BonusLimit,
+		MaxRedemptions: c.MaxRedemptions,
+		PerUserLimit:   c.PerUserLimit,
+		ValidFrom:      formatTime(c.ValidFrom),
+		ValidUntil:     formatTime(c.ValidUntil),
+		PlanIds:        c.PlanIDs,
+		DisabledAt:     formatTime(c.DisabledAt),
+		CreatedAt:      formatTime(c.CreatedAt),
+	}
+}
+
+func toRefundAudit(a *data.RefundAudit) *admin.RefundAudit {
+	return &admin.RefundAudit{
+		Id:              a.ID,
+		SubscriptionId:  a.SubscriptionID,
+		UserId:          a.UserID,
+		ActorId:         a.ActorID,
+		ReasonCode:      a.ReasonCode,
+		Note:            a.Note,
+		Policy:          a.Quote.Policy,
+		PaymentIntentId: a.PaymentIntentID,
+		PaidAmount:      a.Quote.Paid,
+		UnusedTime:      a.Quote.UnusedTime,
+		UnusedLimit:     a.Quote.UnusedLimit,
+		Amount:          a.Quote.Amount,
+		Currency:        a.Currency,
+		CreatedAt:       formatTime(a.CreatedAt),
+		PaidOutAt:       formatTime(a.PaidOutAt),
+	}
+}
+
+// parseTime parses an optional RFC 3339 request field, recording an error
+// against key if it is malformed.
+func parseTime(v *validator.Validator, key, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	v.Check(err == nil, key, "must be an RFC 3339 time")
+	return t
+}
+
+// formatTime formats t as RFC 3339, leaving the zero time empty.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -6,10 +6,31 @@ import (
 	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"strconv"
 	"strings"
 	"subscriptionMService/internal/contextkeys"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/money"
 	"subscriptionMService/internal/validator"
+	"time"
+)
+
+// Metadata keys carrying optional request parameters.
+const (
+	cancelReasonKey     = "cancel-reason"
+	balanceReasonKey    = "balance-reason"
+	balanceReferenceKey = "balance-reference"
+	changeAtKey         = "change-at"
+	couponCodeKey       = "coupon-code"
+	currencyKey         = "currency"
+)
+
+// Values accepted in the change-at metadata key.
+const (
+	changeAtNow       = "now"
+	changeAtPeriodEnd = "period_end"
 )
 
 type serverAPI struct {
@@ -18,34 +39,55 @@ type serverAPI struct {
 }
 
 type Subscription interface {
-	Subscribe(ctx context.Context, planId int32) (int64, subs.Status)
-	ChangeSubsPlan(ctx context.Context, newPlanId int32) subs.Status
-	Unsubscribe(ctx context.Context) subs.Status
+	Account
+	SubscriptionAdmin
+	Subscribe(ctx context.Context, planId int32, couponCode, currency string) (int64, subs.Status)
+	ChangeSubsPlan(ctx context.Context, newPlanId int32, atPeriodEnd bool, couponCode string) (data.PlanChange, subs.Status)
+	CheckCoupon(ctx context.Context, code string, planId int32) error
+	GetPendingPlanChange(ctx context.Context) (data.PlanChange, subs.Status)
+	GetTrialEnd(ctx context.Context) (time.Time, subs.Status)
+	GetPlanVersion(ctx context.Context) (int32, subs.Status)
+	Unsubscribe(ctx context.Context, reason string) subs.Status
 	GetSubDetails(ctx context.Context) (int32, string, int32, string)
 	CheckSubscription(ctx context.Context) subs.Status
-	ListPlans(ctx context.Context) []*subs.Plan
-	ExtractFromBalance(ctx context.Context, value int64) (subs.Status, string, int64)
-	AddToBalance(ctx context.Context, value int64) (subs.Status, string, int64)
+	ListPlans(ctx context.Context, currency string) []*subs.Plan
+	ExtractFromBalance(ctx context.Context, value int64, reason, reference string) (subs.Status, string, int64)
+	AddToBalance(ctx context.Context, value int64, reason, reference string) (subs.Status, string, int64)
 }
 
-func Register(gRPC *grpc.Server, subscription Subscription) {
+func Register(gRPC *grpc.Server, subscription Subscription, plans Plans) {
 	subs.RegisterSubscriptionServer(gRPC, &serverAPI{subs: subscription})
+	registerAccount(gRPC, subscription)
+	registerAdmin(gRPC, subscription, plans)
 }
 
 func (s *serverAPI) Subscribe(ctx context.Context, r *subs.SubsRequest) (*subs.SubsResponse, error) {
 	v := validator.New()
 
 	planID := r.GetPlanId()
+	couponCode := metadataValue(ctx, couponCodeKey)
 
 	v.Check(planID != 0, "text", "plan_id not found or invalid")
+	v.Check(len(couponCode) <= 64, "coupon_code", "must not be more than 64 bytes long")
 
 	if !v.Valid() {
 		return nil, collectErrors(v)
 	}
 
-	subID, isCompleted := s.subs.Subscribe(ctx, planID)
+	currency, err := currencyMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if couponCode != "" {
+		if err := s.subs.CheckCoupon(ctx, couponCode, planID); err != nil {
+			return nil, err
+		}
+	}
 
-	// TODO: Отправить сообщение на почту
+	// The confirmation email is sent from the subscription events by the
+	// notifications dispatcher.
+	subID, isCompleted := s.subs.Subscribe(ctx, planID, couponCode, currency)
 
 	return &subs.SubsResponse{
 		SubId:  subID,
@@ -59,7 +101,12 @@ func (s *serverAPI) ExtractFromBalance(ctx context.Context, r *subs.ExtractFromB
 		return nil, fmt.Errorf("value cannot be 0!")
 	}
 
-	opStatus, msg, valueLeft := s.subs.ExtractFromBalance(ctx, value)
+	reason, reference, err := balanceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opStatus, msg, valueLeft := s.subs.ExtractFromBalance(ctx, value, reason, reference)
 	if opStatus != subs.Status_STATUS_OK {
 		return nil, s.MapStatusToError(opStatus)
 	}
@@ -77,7 +124,12 @@ func (s *serverAPI) AddToBalance(ctx context.Context, r *subs.AddToBalanceReques
 		return nil, fmt.Errorf("value cannot be 0!")
 	}
 
-	opStatus, msg, valueLeft := s.subs.AddToBalance(ctx, value)
+	reason, reference, err := balanceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opStatus, msg, valueLeft := s.subs.AddToBalance(ctx, value, reason, reference)
 	if opStatus != subs.Status_STATUS_OK {
 		return nil, s.MapStatusToError(opStatus)
 	}
@@ -92,25 +144,58 @@ func (s *serverAPI) ChangeSubsPlan(ctx context.Context, r *subs.ChangePlanReques
 	v := validator.New()
 
 	NewPlanID := r.GetNewPlanId()
+	changeAt := metadataValue(ctx, changeAtKey)
+	couponCode := metadataValue(ctx, couponCodeKey)
 
 	v.Check(NewPlanID != 0, "text", "plan_id not found or invalid")
+	v.Check(changeAt == "" || changeAt == changeAtNow || changeAt == changeAtPeriodEnd, "change_at", "must be now or period_end")
+	v.Check(len(couponCode) <= 64, "coupon_code", "must not be more than 64 bytes long")
 
 	if !v.Valid() {
 		return nil, collectErrors(v)
 	}
 
-	resStatus := s.subs.ChangeSubsPlan(ctx, NewPlanID)
+	if couponCode != "" {
+		if err := s.subs.CheckCoupon(ctx, couponCode, NewPlanID); err != nil {
+			return nil, err
+		}
+	}
+
+	change, resStatus := s.subs.ChangeSubsPlan(ctx, NewPlanID, changeAt == changeAtPeriodEnd, couponCode)
 
+	if resStatus == subs.Status_STATUS_NOT_SUBSCRIBED {
+		return nil, status.Error(codes.FailedPrecondition, "payment for the plan change was declined")
+	}
 	if resStatus != subs.Status_STATUS_OK {
 		return nil, s.MapStatusToError(resStatus)
 	}
 
+	// ChangePlanResponse has no room for the proration, so it is returned in
+	// the response header.
+	grpc.SetHeader(ctx, metadata.Pairs(
+		"proration-kind", change.Kind,
+		"proration-immediate", strconv.FormatBool(change.Immediate),
+		"proration-amount", strconv.FormatInt(change.Amount, 10),
+		"proration-limit-top-up", strconv.Itoa(int(change.LimitTopUp)),
+		"proration-effective-at", change.EffectiveAt.Format(time.RFC3339),
+	))
+
 	return &subs.ChangePlanResponse{Status: resStatus}, nil
 
 }
 
 func (s *serverAPI) Unsubscribe(ctx context.Context, r *subs.UnSubsRequest) (*subs.UnSubsResponse, error) {
-	resStatus := s.subs.Unsubscribe(ctx)
+	v := validator.New()
+
+	reason := metadataValue(ctx, cancelReasonKey)
+
+	v.Check(len(reason) <= 500, "cancel_reason", "must not be more than 500 bytes long")
+
+	if !v.Valid() {
+		return nil, collectErrors(v)
+	}
+
+	resStatus := s.subs.Unsubscribe(ctx, reason)
 	if resStatus != subs.Status_STATUS_OK {
 		return nil, s.MapStatusToError(resStatus)
 	}
@@ -126,6 +211,20 @@ func (s *serverAPI) GetSubDetails(ctx context.Context, r *subs.GetSubRequest) (*
 		return nil, status.Error(codes.Unauthenticated, "user_id not found or invalid")
 	}
 	planId, planName, remainingLimit, expiresAt := s.subs.GetSubDetails(ctx)
+
+	// GetSubResponse has no fields for a scheduled plan change, so it is
+	// reported in the response header.
+	if pending, opStatus := s.subs.GetPendingPlanChange(ctx); opStatus == subs.Status_STATUS_OK && pending.PlanID != 0 {
+		grpc.SetHeader(ctx, metadata.Pairs(
+			"pending-plan-id", strconv.Itoa(int(pending.PlanID)),
+			"pending-change-at", pending.EffectiveAt.Format(time.RFC3339),
+		))
+	}
+	// Nor does it have one for the plan version the user is pinned to.
+	if version, opStatus := s.subs.GetPlanVersion(ctx); opStatus == subs.Status_STATUS_OK {
+		grpc.SetHeader(ctx, metadata.Pairs("plan-version", strconv.Itoa(int(version))))
+	}
+	s.setTrialHeader(ctx)
 	return &subs.GetSubResponse{
 		UserId:         userID,
 		PlanId:         planId,
@@ -141,11 +240,33 @@ func (s *serverAPI) CheckSubscription(ctx context.Context, r *subs.CheckSubsRequ
 	if isSubscribed == subs.Status_STATUS_INTERNAL_ERROR {
 		return nil, s.MapStatusToError(isSubscribed)
 	}
+	s.setTrialHeader(ctx)
 	return &subs.CheckSubsResponse{SubStatus: isSubscribed}, nil
 }
 
+// setTrialHeader reports a running free trial in the response header, since
+// the Status enum has no trialing value.
+func (s *serverAPI) setTrialHeader(ctx context.Context) {
+	trialEndsAt, opStatus := s.subs.GetTrialEnd(ctx)
+	if opStatus != subs.Status_STATUS_OK || trialEndsAt.IsZero() {
+		return
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(
+		"subscription-status", "trialing",
+		"trial-ends-at", trialEndsAt.Format(time.RFC3339),
+	))
+}
+
 func (s *serverAPI) ListPlans(ctx context.Context, r *subs.PlansRequest) (*subs.PlansResponse, error) {
-	plans := s.subs.ListPlans(ctx)
+	currency, err := currencyMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prices are in minor units of the currency, which Plan has no field
+	// for, so it is echoed in the response header.
+	grpc.SetHeader(ctx, metadata.Pairs(currencyKey, currency))
+	plans := s.subs.ListPlans(ctx, currency)
 	planPointers := make([]*subs.Plan, len(plans))
 	for i := range plans {
 		planPointers[i] = plans[i]
@@ -153,6 +274,55 @@ func (s *serverAPI) ListPlans(ctx context.Context, r *subs.PlansRequest) (*subs.
 	return &subs.PlansResponse{Plans: planPointers}, nil
 }
 
+// metadataValue returns the first value of an incoming metadata key, used for
+// optional request parameters the proto messages do not carry.
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// balanceMetadata reads the optional ledger reason and reference sent with
+// balance mutations.
+func balanceMetadata(ctx context.Context) (string, string, error) {
+	v := validator.New()
+
+	reason := metadataValue(ctx, balanceReasonKey)
+	reference := metadataValue(ctx, balanceReferenceKey)
+
+	v.Check(len(reason) <= 100, "balance_reason", "must not be more than 100 bytes long")
+	v.Check(len(reference) <= 200, "balance_reference", "must not be more than 200 bytes long")
+
+	if !v.Valid() {
+		return "", "", collectErrors(v)
+	}
+	return reason, reference, nil
+}
+
+// currencyMetadata reads the optional ISO 4217 currency code prices are
+// quoted in, falling back to the default currency.
+func currencyMetadata(ctx context.Context) (string, error) {
+	v := validator.New()
+
+	currency := strings.ToUpper(metadataValue(ctx, currencyKey))
+	if currency == "" {
+		return money.Default, nil
+	}
+
+	v.Check(money.Supported(currency), "currency", "must be a supported currency code")
+
+	if !v.Valid() {
+		return "", collectErrors(v)
+	}
+	return currency, nil
+}
+
 func collectErrors(v *validator.Validator) error {
 	var b strings.Builder
 	for field, msg := range v.Errors {
@@ -162,6 +332,10 @@ func collectErrors(v *validator.Validator) error {
 }
 
 func (s *serverAPI) MapStatusToError(code subs.Status) error {
+	return mapStatusToError(code)
+}
+
+func mapStatusToError(code subs.Status) error {
 	switch code {
 	case subs.Status_STATUS_INVALID_PLAN:
 		return status.Error(codes.InvalidArgument, "Invalid plan")
@@ -0,0 +1,79 @@
+package invoices
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/money"
+	"time"
+)
+
+var titles = map[string]string{
+	data.InvoiceSubscribe:  "Invoice",
+	data.InvoiceRenewal:    "Invoice",
+	data.InvoiceProration:  "Invoice",
+	data.InvoiceCreditNote: "Credit note",
+}
+
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} {{.Invoice.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 0.4em; border-bottom: 1px solid #ccc; text-align: left; }
+td.amount, th.amount { text-align: right; }
+</style>
+</head>
+<body>
+<h1>{{.Title}} {{.Invoice.Number}}</h1>
+<p>
+Status: {{.Invoice.Status}}<br>
+Issued: {{date .Invoice.IssuedAt}}<br>
+{{if not .Invoice.PaidAt.IsZero}}Paid: {{date .Invoice.PaidAt}}<br>{{end}}
+Period: {{date .Invoice.PeriodStart}} – {{date .Invoice.PeriodEnd}}<br>
+Customer: {{.Invoice.UserID}}, subscription {{.Invoice.SubscriptionID}}
+</p>
+<table>
+<tr><th>Description</th><th class="amount">Qty</th><th class="amount">Unit price</th><th class="amount">Amount</th></tr>
+{{range .Invoice.Lines}}<tr><td>{{.Description}}</td><td class="amount">{{.Quantity}}</td><td class="amount">{{amount .UnitAmount}}</td><td class="amount">{{amount .Amount}}</td></tr>
+{{end}}<tr><td colspan="3">Subtotal</td><td class="amount">{{amount .Invoice.Subtotal}}</td></tr>
+{{if .Invoice.TaxName}}<tr><td colspan="3">{{.Invoice.TaxName}} {{.TaxRate}}</td><td class="amount">{{amount .Invoice.TaxAmount}}</td></tr>
+{{end}}<tr><th colspan="3">Total</th><th class="amount">{{amount .Invoice.Total}}</th></tr>
+</table>
+</body>
+</html>
+`
+
+// RenderHTML writes invoice as a standalone HTML page. Its lines must be
+// loaded.
+func RenderHTML(w io.Writer, invoice *data.Invoice) error {
+	t, err := template.New("invoice").Funcs(template.FuncMap{
+		"amount": func(amount int64) string { return money.Format(amount, invoice.Currency) },
+		"date":   func(t time.Time) string { return t.Format("2006-01-02") },
+	}).Parse(page)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "invoices.RenderHTML", err)
+	}
+
+	title, ok := titles[invoice.Kind]
+	if !ok {
+		title = "Invoice"
+	}
+	td := struct {
+		Title   string
+		TaxRate string
+		Invoice *data.Invoice
+	}{
+		Title:   title,
+		TaxRate: fmt.Sprintf("%d.%02d%%", invoice.TaxRate/100, invoice.TaxRate%100),
+		Invoice: invoice,
+	}
+	if err := t.Execute(w, td); err != nil {
+		return fmt.Errorf("%s:%w", "invoices.RenderHTML", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Default is the currency of plan prices stored before per-currency pricing
+// existed, and the one used when a request does not name a currency.
+const Default = "KZT"
+
+// exponents holds the number of minor units digits of every supported ISO
+// 4217 currency. All amounts in the service are integers in minor units, so
+// for zero-decimal currencies such as JPY one minor unit is one yen.
+var exponents = map[string]int{
+	"KZT": 2,
+	"RUB": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"UZS": 2,
+	"KGS": 2,
+	"TRY": 2,
+	"CNY": 2,
+	"AED": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+	"CLP": 0,
+	"ISK": 0,
+	"UGX": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+	"JOD": 3,
+}
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrInvalidAmount       = errors.New("invalid amount")
+)
+
+// Supported reports whether currency is a known upper-case ISO 4217 code.
+func Supported(currency string) bool {
+	_, ok := exponents[currency]
+	return ok
+}
+
+// Exponent returns the number of decimal places of currency's minor unit.
+func Exponent(currency string) int {
+	return exponents[currency]
+}
+
+// Round rounds a fractional amount of minor units to a whole one, half away
+// from zero. Prorated amounts are rounded this way so that a zero-decimal
+// currency never ends up with a fraction of its smallest coin.
+func Round(amount float64) int64 {
+	return int64(math.Round(amount))
+}
+
+// Parse converts a decimal amount in major units, such as "12.50", to minor
+// units of currency. More decimal places than the currency has is an error
+// rather than being rounded, so "100.5" is rejected for JPY.
+func Parse(s, currency string) (int64, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return 0, ErrUnsupportedCurrency
+	}
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || (hasFrac && frac == "") || len(frac) > exp {
+		return 0, ErrInvalidAmount
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || amount < 0 {
+		return 0, ErrInvalidAmount
+	}
+	return amount, nil
+}
+
+// Format renders an amount of minor units in major units with the currency's
+// number of decimal places, for example "12.50 USD" or "1250 JPY".
+func Format(amount int64, currency string) string {
+	exp := exponents[currency]
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	if exp == 0 {
+		return sign + digits + " " + currency
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:] + " " + currency
+}
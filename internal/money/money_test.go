@@ -0,0 +1,135 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s        string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{s: "12.50", currency: "USD", want: 1250},
+		{s: "12.5", currency: "USD", want: 1250},
+		{s: "12", currency: "USD", want: 1200},
+		{s: "0.01", currency: "USD", want: 1},
+		{s: "0", currency: "USD", want: 0},
+		{s: "12.505", currency: "USD", wantErr: ErrInvalidAmount},
+		{s: "1250", currency: "JPY", want: 1250},
+		{s: "100.5", currency: "JPY", wantErr: ErrInvalidAmount},
+		{s: "100.", currency: "JPY", wantErr: ErrInvalidAmount},
+		{s: "15000", currency: "KRW", want: 15000},
+		{s: "15000.0", currency: "KRW", wantErr: ErrInvalidAmount},
+		{s: "1.234", currency: "KWD", want: 1234},
+		{s: "", currency: "USD", wantErr: ErrInvalidAmount},
+		{s: ".50", currency: "USD", wantErr: ErrInvalidAmount},
+		{s: "12.", currency: "USD", wantErr: ErrInvalidAmount},
+		{s: "-1.00", currency: "USD", wantErr: ErrInvalidAmount},
+		{s: "abc", currency: "USD", wantErr: ErrInvalidAmount},
+		{s: "12.50", currency: "XXX", wantErr: ErrUnsupportedCurrency},
+		{s: "12.50", currency: "usd", wantErr: ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.s, tt.currency)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Parse(%q, %s) error = %v, want %v", tt.s, tt.currency, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q, %s) = %d, want %d", tt.s, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{amount: 1250, currency: "USD", want: "12.50 USD"},
+		{amount: 5, currency: "USD", want: "0.05 USD"},
+		{amount: 50, currency: "USD", want: "0.50 USD"},
+		{amount: 0, currency: "USD", want: "0.00 USD"},
+		{amount: -1250, currency: "USD", want: "-12.50 USD"},
+		{amount: 1250, currency: "JPY", want: "1250 JPY"},
+		{amount: 0, currency: "JPY", want: "0 JPY"},
+		{amount: -300, currency: "JPY", want: "-300 JPY"},
+		{amount: 15000, currency: "KRW", want: "15000 KRW"},
+		{amount: 1234, currency: "KWD", want: "1.234 KWD"},
+		{amount: 7, currency: "KWD", want: "0.007 KWD"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("Format(%d, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		s        string
+		currency string
+	}{
+		{s: "12.50", currency: "USD"},
+		{s: "0.01", currency: "USD"},
+		{s: "1250", currency: "JPY"},
+		{s: "15000", currency: "KRW"},
+	}
+
+	for _, tt := range tests {
+		amount, err := Parse(tt.s, tt.currency)
+		if err != nil {
+			t.Fatalf("Parse(%q, %s) = %v", tt.s, tt.currency, err)
+		}
+		if got, want := Format(amount, tt.currency), tt.s+" "+tt.currency; got != want {
+			t.Errorf("Format(Parse(%q)) = %q, want %q", tt.s, got, want)
+		}
+	}
+}
+
+func TestExponent(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     int
+	}{
+		{currency: "USD", want: 2},
+		{currency: "KZT", want: 2},
+		{currency: "JPY", want: 0},
+		{currency: "KRW", want: 0},
+		{currency: "KWD", want: 3},
+	}
+
+	for _, tt := range tests {
+		if !Supported(tt.currency) {
+			t.Errorf("Supported(%s) = false, want true", tt.currency)
+		}
+		if got := Exponent(tt.currency); got != tt.want {
+			t.Errorf("Exponent(%s) = %d, want %d", tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   int64
+	}{
+		{amount: 0.4, want: 0},
+		{amount: 0.5, want: 1},
+		{amount: 1249.5, want: 1250},
+		{amount: -0.5, want: -1},
+		{amount: -1.4, want: -1},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.amount); got != tt.want {
+			t.Errorf("Round(%v) = %d, want %d", tt.amount, got, tt.want)
+		}
+	}
+}
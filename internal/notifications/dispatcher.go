@@ -0,0 +1,152 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/storage/postgres"
+	"time"
+)
+
+// Dispatcher turns subscription events from the outbox into notifications.
+// It implements outbox.Publisher, so notifications are sent by the outbox
+// relay rather than inline from the handlers, and a failed send is retried
+// with the relay's backoff.
+type Dispatcher struct {
+	log      *jsonlog.Logger
+	notifier Notifier
+	store    dispatcherStore
+}
+
+type dispatcherStore interface {
+	GetContact(ctx context.Context, userId int64) (*data.Contact, error)
+	GetPlan(ctx context.Context, planId int32) (*data.Plan, error)
+}
+
+// eventPayload is the JSON snapshot stored with every outbox message.
+type eventPayload struct {
+	EventType      string `json:"event_type"`
+	UserID         int64  `json:"user_id"`
+	PlanID         int32  `json:"plan_id"`
+	Status         string `json:"status"`
+	RemainingLimit int32  `json:"remaining_limit"`
+	ExpiresAt      string `json:"expires_at"`
+	Details        string `json:"details"`
+}
+
+// payloadTimeLayout is how Postgres renders TIMESTAMP values in JSON.
+const payloadTimeLayout = "2006-01-02T15:04:05.999999"
+
+func NewDispatcher(log *jsonlog.Logger, notifier Notifier, store dispatcherStore) *Dispatcher {
+	return &Dispatcher{
+		log:      log,
+		notifier: notifier,
+		store:    store,
+	}
+}
+
+func (d *Dispatcher) Publish(ctx context.Context, msg data.OutboxMessage) error {
+	var payload eventPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("%s:%w", "notifications.Dispatcher.Publish", err)
+	}
+
+	kind := kindFor(payload)
+	if kind == "" {
+		return nil
+	}
+
+	contact, err := d.store.GetContact(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrContactNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !contact.NotificationsEnabled {
+		return nil
+	}
+
+	td := TemplateData{
+		RemainingLimit: payload.RemainingLimit,
+		ExpiresAt:      payload.ExpiresAt,
+	}
+	if expiresAt, err := time.Parse(payloadTimeLayout, payload.ExpiresAt); err == nil {
+		td.ExpiresAt = expiresAt.Format("02.01.2006")
+	}
+	if kind == KindPaymentFailed {
+		// Scheduled retries carry their time in the event details.
+		if retryAt, err := time.Parse(time.RFC3339, payload.Details); err == nil {
+			td.RetryAt = retryAt.Format("02.01.2006")
+		}
+	}
+	if plan, err := d.store.GetPlan(ctx, payload.PlanID); err == nil {
+		td.PlanName = plan.Name
+	} else if !errors.Is(err, postgres.ErrPlanNotFound) {
+		return err
+	}
+
+	subject, body, err := Render(kind, contact.Locale, td)
+	if err != nil {
+		return err
+	}
+
+	err = d.notifier.Notify(ctx, Message{
+		UserID:  payload.UserID,
+		To:      contact.Email,
+		Kind:    kind,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.log.PrintInfo("notification sent", map[string]string{
+		"method":   "notifications.Dispatcher.Publish",
+		"event_id": fmt.Sprint(msg.EventID),
+		"user_id":  fmt.Sprint(payload.UserID),
+		"kind":     kind,
+	})
+	return nil
+}
+
+// kindFor picks the notification for an event, or "" if it needs none.
+func kindFor(payload eventPayload) string {
+	switch payload.EventType {
+	case data.EventSubscribed, data.EventReactivated, data.EventActivated:
+		// Subscriptions still waiting for their bucket are announced once
+		// they are activated.
+		if payload.Status == "active" || payload.Status == "trialing" {
+			return KindSubscribed
+		}
+	case data.EventExpired:
+		if payload.Details == data.DunningGaveUp {
+			return KindPaymentExhausted
+		}
+		return KindExpired
+	case data.EventPaymentDisputed:
+		return KindRestricted
+	case data.EventDunningScheduled:
+		return KindPaymentFailed
+	case data.EventRenewed:
+		if payload.Details == data.DunningRecovered {
+			return KindPaymentRecovered
+		}
+	case data.EventCancelled:
+		return KindCancelled
+	case data.EventReminder:
+		switch payload.Details {
+		case data.ReminderRenewalUpcoming:
+			return KindRenewalUpcoming
+		case data.ReminderExpiring:
+			return KindExpiring
+		case data.ReminderLowLimit:
+			return KindLowLimit
+		}
+	}
+	return ""
+}
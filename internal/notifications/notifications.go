@@ -0,0 +1,111 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+	"subscriptionMService/internal/jsonlog"
+	"sync"
+	"time"
+)
+
+// Message is a rendered notification ready to be sent.
+type Message struct {
+	UserID  int64
+	To      string
+	Kind    string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to users.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// SMTPNotifier sends messages as plain-text email.
+type SMTPNotifier struct {
+	addr   string
+	auth   smtp.Auth
+	sender string
+}
+
+func NewSMTPNotifier(host string, port int, username, password, sender string) *SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPNotifier{
+		addr:   fmt.Sprintf("%s:%d", host, port),
+		auth:   auth,
+		sender: sender,
+	}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.sender)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	err := smtp.SendMail(n.addr, n.auth, n.sender, []string{msg.To}, []byte(b.String()))
+	if err != nil {
+		return fmt.Errorf("%s:%w", "notifications.SMTPNotifier.Notify", err)
+	}
+	return nil
+}
+
+// FileNotifier appends each message as a JSON line to a file. It is meant for
+// local testing.
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Notify(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time
+	}{msg, time.Now()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return errors.Join(err, f.Close())
+}
+
+// LogNotifier writes each message to the log instead of sending it.
+type LogNotifier struct {
+	Log *jsonlog.Logger
+}
+
+func (n LogNotifier) Notify(ctx context.Context, msg Message) error {
+	n.Log.PrintInfo("notification", map[string]string{
+		"user_id": fmt.Sprint(msg.UserID),
+		"to":      msg.To,
+		"kind":    msg.Kind,
+		"subject": msg.Subject,
+		"body":    msg.Body,
+	})
+	return nil
+}
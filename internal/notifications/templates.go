@@ -0,0 +1,177 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"subscriptionMService/internal/data"
+	"text/template"
+)
+
+// Notification kinds.
+const (
+	KindSubscribed       = "subscribed"
+	KindRenewalUpcoming  = "renewal_upcoming"
+	KindExpiring         = "expiring"
+	KindExpired          = "expired"
+	KindLowLimit         = "low_limit"
+	KindCancelled        = "cancelled"
+	KindPaymentFailed    = "payment_failed"
+	KindPaymentRecovered = "payment_recovered"
+	KindPaymentExhausted = "payment_exhausted"
+	KindRestricted       = "restricted"
+)
+
+// TemplateData is what message templates can refer to.
+type TemplateData struct {
+	PlanName       string
+	RemainingLimit int32
+	ExpiresAt      string
+	RetryAt        string
+}
+
+type messageTemplate struct {
+	subject string
+	body    string
+}
+
+var templateText = map[string]map[string]messageTemplate{
+	KindSubscribed: {
+		data.LocaleRU: {
+			subject: "Подписка «{{.PlanName}}» оформлена",
+			body:    "Спасибо за подписку «{{.PlanName}}»!\nДоступно аренд: {{.RemainingLimit}}.\nПодписка действует до {{.ExpiresAt}}.",
+		},
+		data.LocaleEN: {
+			subject: "Your {{.PlanName}} subscription is active",
+			body:    "Thank you for subscribing to {{.PlanName}}!\nRentals available: {{.RemainingLimit}}.\nYour subscription runs until {{.ExpiresAt}}.",
+		},
+	},
+	KindRenewalUpcoming: {
+		data.LocaleRU: {
+			subject: "Подписка «{{.PlanName}}» скоро продлится",
+			body:    "Ваша подписка «{{.PlanName}}» заканчивается {{.ExpiresAt}}.\nПроверьте, что на балансе достаточно средств для продления.",
+		},
+		data.LocaleEN: {
+			subject: "Your {{.PlanName}} subscription renews soon",
+			body:    "Your {{.PlanName}} subscription ends on {{.ExpiresAt}}.\nPlease make sure your wallet has enough funds for the renewal.",
+		},
+	},
+	KindExpiring: {
+		data.LocaleRU: {
+			subject: "Подписка «{{.PlanName}}» скоро закончится",
+			body:    "Ваша подписка «{{.PlanName}}» закончится {{.ExpiresAt}} и не будет продлена автоматически.\nОформите её снова, чтобы не потерять доступ.",
+		},
+		data.LocaleEN: {
+			subject: "Your {{.PlanName}} subscription ends soon",
+			body:    "Your {{.PlanName}} subscription ends on {{.ExpiresAt}} and will not renew automatically.\nSubscribe again to keep your access.",
+		},
+	},
+	KindExpired: {
+		data.LocaleRU: {
+			subject: "Подписка «{{.PlanName}}» закончилась",
+			body:    "Срок действия подписки «{{.PlanName}}» истёк {{.ExpiresAt}}.\nОформите подписку снова, чтобы продолжить аренду игрушек.",
+		},
+		data.LocaleEN: {
+			subject: "Your {{.PlanName}} subscription has expired",
+			body:    "Your {{.PlanName}} subscription expired on {{.ExpiresAt}}.\nSubscribe again to keep renting toys.",
+		},
+	},
+	KindLowLimit: {
+		data.LocaleRU: {
+			subject: "Осталось мало аренд",
+			body:    "По подписке «{{.PlanName}}» осталось аренд: {{.RemainingLimit}}.\nЛимит обновится {{.ExpiresAt}}.",
+		},
+		data.LocaleEN: {
+			subject: "You are running low on rentals",
+			body:    "You have {{.RemainingLimit}} rentals left on your {{.PlanName}} subscription.\nYour limit resets on {{.ExpiresAt}}.",
+		},
+	},
+	KindCancelled: {
+		data.LocaleRU: {
+			subject: "Подписка «{{.PlanName}}» отменена",
+			body:    "Подписка «{{.PlanName}}» отменена.\nДоступ сохранится до {{.ExpiresAt}}.",
+		},
+		data.LocaleEN: {
+			subject: "Your {{.PlanName}} subscription was cancelled",
+			body:    "Your {{.PlanName}} subscription has been cancelled.\nYou keep access until {{.ExpiresAt}}.",
+		},
+	},
+	KindPaymentFailed: {
+		data.LocaleRU: {
+			subject: "Не удалось оплатить подписку «{{.PlanName}}»",
+			body:    "Нам не удалось списать оплату за подписку «{{.PlanName}}».\nМы повторим попытку {{.RetryAt}}. До успешной оплаты новые аренды недоступны.",
+		},
+		data.LocaleEN: {
+			subject: "Payment for your {{.PlanName}} subscription failed",
+			body:    "We could not collect the payment for your {{.PlanName}} subscription.\nWe will try again on {{.RetryAt}}. New rentals are on hold until the payment goes through.",
+		},
+	},
+	KindPaymentRecovered: {
+		data.LocaleRU: {
+			subject: "Подписка «{{.PlanName}}» оплачена",
+			body:    "Оплата за подписку «{{.PlanName}}» прошла успешно.\nДоступно аренд: {{.RemainingLimit}}.\nПодписка действует до {{.ExpiresAt}}.",
+		},
+		data.LocaleEN: {
+			subject: "Your {{.PlanName}} subscription is paid",
+			body:    "The payment for your {{.PlanName}} subscription went through.\nRentals available: {{.RemainingLimit}}.\nYour subscription runs until {{.ExpiresAt}}.",
+		},
+	},
+	KindPaymentExhausted: {
+		data.LocaleRU: {
+			subject: "Подписка «{{.PlanName}}» закончилась",
+			body:    "Нам так и не удалось списать оплату за подписку «{{.PlanName}}», и она закончилась.\nОформите подписку снова, чтобы продолжить аренду игрушек.",
+		},
+		data.LocaleEN: {
+			subject: "Your {{.PlanName}} subscription has ended",
+			body:    "We were unable to collect the payment for your {{.PlanName}} subscription, so it has ended.\nSubscribe again to keep renting toys.",
+		},
+	},
+	KindRestricted: {
+		data.LocaleRU: {
+			subject: "Подписка «{{.PlanName}}» приостановлена",
+			body:    "Платёж за подписку «{{.PlanName}}» оспорен, поэтому аренда игрушек по ней приостановлена.\nЕсли это ошибка, свяжитесь с поддержкой.",
+		},
+		data.LocaleEN: {
+			subject: "Your {{.PlanName}} subscription is on hold",
+			body:    "The payment for your {{.PlanName}} subscription was disputed, so renting toys with it is on hold.\nIf this is a mistake, please contact support.",
+		},
+	},
+}
+
+var templates = parseTemplates()
+
+func parseTemplates() map[string]map[string][2]*template.Template {
+	parsed := make(map[string]map[string][2]*template.Template)
+	for kind, locales := range templateText {
+		parsed[kind] = make(map[string][2]*template.Template)
+		for locale, t := range locales {
+			name := kind + "." + locale
+			parsed[kind][locale] = [2]*template.Template{
+				template.Must(template.New(name + ".subject").Parse(t.subject)),
+				template.Must(template.New(name + ".body").Parse(t.body)),
+			}
+		}
+	}
+	return parsed
+}
+
+// Render fills in the subject and body of kind in locale, falling back to
+// Russian for unknown locales.
+func Render(kind, locale string, td TemplateData) (string, string, error) {
+	locales, ok := templates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("%s: unknown notification kind %q", "notifications.Render", kind)
+	}
+	t, ok := locales[locale]
+	if !ok {
+		t = locales[data.LocaleRU]
+	}
+
+	var subject, body strings.Builder
+	if err := t[0].Execute(&subject, td); err != nil {
+		return "", "", fmt.Errorf("%s:%w", "notifications.Render", err)
+	}
+	if err := t[1].Execute(&body, td); err != nil {
+		return "", "", fmt.Errorf("%s:%w", "notifications.Render", err)
+	}
+	return subject.String(), body.String(), nil
+}
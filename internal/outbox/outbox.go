@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"sync"
+)
+
+// Publisher delivers outbox messages to the rest of the system. Delivery is
+// at-least-once: a message may be published again if marking it delivered
+// fails, so implementations and their consumers should tolerate duplicates
+// by EventID.
+type Publisher interface {
+	Publish(ctx context.Context, msg data.OutboxMessage) error
+}
+
+// LogPublisher writes each message to the log.
+type LogPublisher struct {
+	Log *jsonlog.Logger
+}
+
+func (p LogPublisher) Publish(ctx context.Context, msg data.OutboxMessage) error {
+	p.Log.PrintInfo("subscription event published", map[string]string{
+		"event_id":        fmt.Sprint(msg.EventID),
+		"event_type":      msg.EventType,
+		"subscription_id": fmt.Sprint(msg.SubscriptionID),
+		"user_id":         fmt.Sprint(msg.UserID),
+		"payload":         string(msg.Payload),
+	})
+	return nil
+}
+
+// FilePublisher appends each message as a JSON line to a file.
+type FilePublisher struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFilePublisher(path string) *FilePublisher {
+	return &FilePublisher{path: path}
+}
+
+func (p *FilePublisher) Publish(ctx context.Context, msg data.OutboxMessage) error {
+	line, err := json.Marshal(struct {
+		EventID   int64           `json:"event_id"`
+		EventType string          `json:"event_type"`
+		Payload   json.RawMessage `json:"payload"`
+	}{msg.EventID, msg.EventType, msg.Payload})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	f, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return errors.Join(err, f.Close())
+}
+
+// MemoryPublisher keeps published messages in memory. It is meant for tests
+// and local runs.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []data.OutboxMessage
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, msg data.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of everything published so far.
+func (p *MemoryPublisher) Messages() []data.OutboxMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]data.OutboxMessage(nil), p.messages...)
+}
+
+// MultiPublisher publishes each message to all of its publishers. It fails if
+// any of them fails, so the message is retried for all of them.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, msg data.OutboxMessage) error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Publish(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
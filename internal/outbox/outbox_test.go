@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"subscriptionMService/internal/data"
+	"sync"
+	"testing"
+)
+
+func TestMemoryPublisherKeepsMessagesInOrder(t *testing.T) {
+	var p MemoryPublisher
+	for i := int64(1); i <= 3; i++ {
+		err := p.Publish(context.Background(), data.OutboxMessage{EventID: i, EventType: data.EventSubscribed})
+		if err != nil {
+			t.Fatalf("Publish(%d) = %v", i, err)
+		}
+	}
+
+	got := p.Messages()
+	if len(got) != 3 {
+		t.Fatalf("len(Messages()) = %d, want 3", len(got))
+	}
+	for i, msg := range got {
+		if msg.EventID != int64(i+1) {
+			t.Errorf("Messages()[%d].EventID = %d, want %d", i, msg.EventID, i+1)
+		}
+	}
+}
+
+func TestMemoryPublisherMessagesReturnsCopy(t *testing.T) {
+	var p MemoryPublisher
+	p.Publish(context.Background(), data.OutboxMessage{EventID: 1})
+
+	got := p.Messages()
+	got[0].EventID = 99
+
+	if id := p.Messages()[0].EventID; id != 1 {
+		t.Errorf("stored EventID = %d after modifying the copy, want 1", id)
+	}
+}
+
+func TestMemoryPublisherConcurrentPublish(t *testing.T) {
+	var p MemoryPublisher
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			p.Publish(context.Background(), data.OutboxMessage{EventID: id})
+		}(int64(i))
+	}
+	wg.Wait()
+
+	if n := len(p.Messages()); n != 50 {
+		t.Errorf("len(Messages()) = %d, want 50", n)
+	}
+}
+
+type failingPublisher struct {
+	err error
+}
+
+func (p failingPublisher) Publish(ctx context.Context, msg data.OutboxMessage) error {
+	return p.err
+}
+
+func TestMultiPublisher(t *testing.T) {
+	errBroker := errors.New("broker down")
+
+	tests := []struct {
+		name    string
+		fail    bool
+		wantErr error
+	}{
+		{name: "all succeed"},
+		{name: "one fails", fail: true, wantErr: errBroker},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := &MemoryPublisher{}, &MemoryPublisher{}
+			multi := MultiPublisher{first}
+			if tt.fail {
+				multi = append(multi, failingPublisher{err: errBroker})
+			}
+			multi = append(multi, last)
+
+			err := multi.Publish(context.Background(), data.OutboxMessage{EventID: 7})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Publish() = %v, want %v", err, tt.wantErr)
+			}
+			// A failing publisher does not stop the others from receiving
+			// the message.
+			if len(first.Messages()) != 1 || len(last.Messages()) != 1 {
+				t.Errorf("published %d and %d messages, want 1 each", len(first.Messages()), len(last.Messages()))
+			}
+		})
+	}
+}
+
+func TestFilePublisherAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	p := NewFilePublisher(path)
+
+	msgs := []data.OutboxMessage{
+		{EventID: 1, EventType: data.EventSubscribed, Payload: []byte(`{"plan_id":1}`)},
+		{EventID: 2, EventType: data.EventCancelled, Payload: []byte(`{"reason":"too expensive"}`)},
+	}
+	for _, msg := range msgs {
+		if err := p.Publish(context.Background(), msg); err != nil {
+			t.Fatalf("Publish(%d) = %v", msg.EventID, err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	type fileLine struct {
+		EventID   int64           `json:"event_id"`
+		EventType string          `json:"event_type"`
+		Payload   json.RawMessage `json:"payload"`
+	}
+	var lines []fileLine
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var l fileLine
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, l)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != len(msgs) {
+		t.Fatalf("file has %d lines, want %d", len(lines), len(msgs))
+	}
+	for i, msg := range msgs {
+		if lines[i].EventID != msg.EventID || lines[i].EventType != msg.EventType || string(lines[i].Payload) != string(msg.Payload) {
+			t.Errorf("line %d = %+v, want %d %s %s", i, lines[i], msg.EventID, msg.EventType, msg.Payload)
+		}
+	}
+}
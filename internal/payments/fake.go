@@ -0,0 +1,146 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"subscriptionMService/internal/data"
+	"sync"
+	"time"
+)
+
+// Gateway operations that can be scripted to fail on the fake gateway.
+const (
+	OpAuthorize = "authorize"
+	OpCapture   = "capture"
+	OpRefund    = "refund"
+)
+
+// FakeGateway is an in-process gateway for development and offline testing.
+// It accepts every payment unless told otherwise: FailNext scripts the next
+// calls of an operation to fail, and DeclineAbove declines authorizations
+// over an amount. Webhooks are signed with the same scheme as real providers.
+type FakeGateway struct {
+	secret string
+
+	mu       sync.Mutex
+	seq      int64
+	script   map[string][]error
+	limit    int64
+	payments map[string]*fakePayment
+}
+
+type fakePayment struct {
+	amount   int64
+	captured bool
+	refunded int64
+}
+
+func NewFakeGateway(secret string) *FakeGateway {
+	return &FakeGateway{
+		secret:   secret,
+		script:   map[string][]error{},
+		payments: map[string]*fakePayment{},
+	}
+}
+
+// FailNext makes the next len(errs) calls of op return errs in order.
+func (g *FakeGateway) FailNext(op string, errs ...error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.script[op] = append(g.script[op], errs...)
+}
+
+// DeclineAbove declines authorizations of more than amount. Zero turns the
+// limit off.
+func (g *FakeGateway) DeclineAbove(amount int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.limit = amount
+}
+
+func (g *FakeGateway) Authorize(ctx context.Context, intent data.PaymentIntent) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.next(OpAuthorize); err != nil {
+		return "", err
+	}
+	if g.limit > 0 && intent.Amount > g.limit {
+		return "", ErrDeclined
+	}
+
+	g.seq++
+	ref := fmt.Sprintf("fake_%d_%d", intent.ID, g.seq)
+	g.payments[ref] = &fakePayment{amount: intent.Amount}
+	return ref, nil
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, intent data.PaymentIntent) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.next(OpCapture); err != nil {
+		return err
+	}
+	payment, ok := g.payments[intent.ProviderRef]
+	if !ok {
+		return ErrPaymentNotFound
+	}
+	payment.captured = true
+	return nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, intent data.PaymentIntent, amount int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.next(OpRefund); err != nil {
+		return err
+	}
+	payment, ok := g.payments[intent.ProviderRef]
+	if !ok {
+		return ErrPaymentNotFound
+	}
+	if !payment.captured || payment.refunded+amount > payment.amount {
+		return ErrRefundExceedsCapture
+	}
+	payment.refunded += amount
+	return nil
+}
+
+func (g *FakeGateway) VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error) {
+	if err := VerifySignature(g.secret, payload, header, time.Now()); err != nil {
+		return nil, err
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// SignWebhook encodes event as a webhook request body and signs it, as the
+// provider would before delivering it.
+func (g *FakeGateway) SignWebhook(event WebhookEvent) ([]byte, http.Header, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set(SignatureHeader, Sign(g.secret, payload, time.Now()))
+	return payload, header, nil
+}
+
+// next pops the next scripted error for op. g.mu must be held.
+func (g *FakeGateway) next(op string) error {
+	errs := g.script[op]
+	if len(errs) == 0 {
+		return nil
+	}
+	g.script[op] = errs[1:]
+	return errs[0]
+}
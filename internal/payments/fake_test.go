@@ -0,0 +1,151 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"subscriptionMService/internal/data"
+	"testing"
+	"time"
+)
+
+func TestFakeGatewayAuthorizeCaptureRefund(t *testing.T) {
+	ctx := context.Background()
+	g := NewFakeGateway("secret")
+
+	intent := data.PaymentIntent{ID: 1, Amount: 1000}
+	ref, err := g.Authorize(ctx, intent)
+	if err != nil {
+		t.Fatalf("Authorize() = %v", err)
+	}
+	if ref == "" {
+		t.Fatal("Authorize() returned an empty reference")
+	}
+	intent.ProviderRef = ref
+
+	if err := g.Refund(ctx, intent, 100); !errors.Is(err, ErrRefundExceedsCapture) {
+		t.Errorf("Refund() before capture = %v, want %v", err, ErrRefundExceedsCapture)
+	}
+	if err := g.Capture(ctx, intent); err != nil {
+		t.Fatalf("Capture() = %v", err)
+	}
+	if err := g.Refund(ctx, intent, 600); err != nil {
+		t.Errorf("Refund(600) = %v", err)
+	}
+	if err := g.Refund(ctx, intent, 500); !errors.Is(err, ErrRefundExceedsCapture) {
+		t.Errorf("Refund(500) over the rest = %v, want %v", err, ErrRefundExceedsCapture)
+	}
+	if err := g.Refund(ctx, intent, 400); err != nil {
+		t.Errorf("Refund(400) = %v", err)
+	}
+}
+
+func TestFakeGatewayUnknownPayment(t *testing.T) {
+	ctx := context.Background()
+	g := NewFakeGateway("secret")
+
+	intent := data.PaymentIntent{ID: 1, Amount: 1000, ProviderRef: "fake_unknown"}
+	if err := g.Capture(ctx, intent); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("Capture() = %v, want %v", err, ErrPaymentNotFound)
+	}
+	if err := g.Refund(ctx, intent, 100); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("Refund() = %v, want %v", err, ErrPaymentNotFound)
+	}
+}
+
+func TestFakeGatewayDeclineAbove(t *testing.T) {
+	tests := []struct {
+		limit   int64
+		amount  int64
+		wantErr error
+	}{
+		{limit: 0, amount: 1_000_000},
+		{limit: 1000, amount: 1000},
+		{limit: 1000, amount: 1001, wantErr: ErrDeclined},
+	}
+
+	for _, tt := range tests {
+		g := NewFakeGateway("secret")
+		g.DeclineAbove(tt.limit)
+
+		_, err := g.Authorize(context.Background(), data.PaymentIntent{ID: 1, Amount: tt.amount})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("limit %d: Authorize(%d) = %v, want %v", tt.limit, tt.amount, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFakeGatewayFailNext(t *testing.T) {
+	ctx := context.Background()
+	g := NewFakeGateway("secret")
+	errTimeout := errors.New("timeout")
+	g.FailNext(OpAuthorize, errTimeout, ErrDeclined)
+
+	intent := data.PaymentIntent{ID: 1, Amount: 1000}
+	if _, err := g.Authorize(ctx, intent); !errors.Is(err, errTimeout) {
+		t.Errorf("first Authorize() = %v, want %v", err, errTimeout)
+	}
+	if _, err := g.Authorize(ctx, intent); !errors.Is(err, ErrDeclined) {
+		t.Errorf("second Authorize() = %v, want %v", err, ErrDeclined)
+	}
+	ref, err := g.Authorize(ctx, intent)
+	if err != nil {
+		t.Fatalf("third Authorize() = %v, want success once the script runs out", err)
+	}
+
+	// Scripted failures are kept per operation.
+	intent.ProviderRef = ref
+	g.FailNext(OpCapture, ErrDeclined)
+	if err := g.Capture(ctx, intent); !errors.Is(err, ErrDeclined) {
+		t.Errorf("Capture() = %v, want %v", err, ErrDeclined)
+	}
+	if err := g.Capture(ctx, intent); err != nil {
+		t.Errorf("Capture() retry = %v", err)
+	}
+}
+
+func TestFakeGatewayWebhooks(t *testing.T) {
+	g := NewFakeGateway("secret")
+	event := WebhookEvent{ID: "evt_1", Type: WebhookSucceeded, ProviderRef: "fake_1_1", Amount: 1000}
+
+	payload, header, err := g.SignWebhook(event)
+	if err != nil {
+		t.Fatalf("SignWebhook() = %v", err)
+	}
+	got, err := g.VerifyWebhook(payload, header)
+	if err != nil {
+		t.Fatalf("VerifyWebhook() = %v", err)
+	}
+	if *got != event {
+		t.Errorf("VerifyWebhook() = %+v, want %+v", *got, event)
+	}
+
+	tampered := append([]byte(nil), payload...)
+	tampered[len(tampered)-2] = '9'
+	if _, err := g.VerifyWebhook(tampered, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyWebhook(tampered) = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	other := NewFakeGateway("other secret")
+	if _, err := other.VerifyWebhook(payload, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyWebhook() with another secret = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	stale := http.Header{}
+	stale.Set(SignatureHeader, Sign("secret", payload, time.Now().Add(-2*SignatureTolerance)))
+	if _, err := g.VerifyWebhook(payload, stale); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyWebhook(stale) = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestFakeGatewayRejectsEmptySecret(t *testing.T) {
+	g := NewFakeGateway("")
+
+	payload, header, err := g.SignWebhook(WebhookEvent{ID: "evt_1", Type: WebhookSucceeded})
+	if err != nil {
+		t.Fatalf("SignWebhook() = %v", err)
+	}
+	if _, err := g.VerifyWebhook(payload, header); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyWebhook() = %v, want %v", err, ErrInvalidSignature)
+	}
+}
@@ -0,0 +1,205 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"time"
+)
+
+var (
+	ErrDeclined             = errors.New("payment declined")
+	ErrInvalidSignature     = errors.New("invalid webhook signature")
+	ErrWebhooksUnsupported  = errors.New("gateway does not send webhooks")
+	ErrPaymentNotFound      = errors.New("payment not found at gateway")
+	ErrRefundExceedsCapture = errors.New("refund exceeds captured amount")
+)
+
+// PaymentGateway is a payment provider. Every call carries the intent it acts
+// on; Authorize returns the provider's reference for the payment, which later
+// calls find in intent.ProviderRef.
+type PaymentGateway interface {
+	Authorize(ctx context.Context, intent data.PaymentIntent) (string, error)
+	Capture(ctx context.Context, intent data.PaymentIntent) error
+	Refund(ctx context.Context, intent data.PaymentIntent, amount int64) error
+	VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error)
+}
+
+// Webhook event types sent by gateways. A succeeded event means the payment
+// was captured. For refunded events Amount is the total refunded so far, so
+// that redelivered and out of order events settle on the same figure.
+const (
+	WebhookAuthorized = "payment.authorized"
+	WebhookSucceeded  = "payment.succeeded"
+	WebhookFailed     = "payment.failed"
+	WebhookRefunded   = "payment.refunded"
+	WebhookDisputed   = "payment.disputed"
+)
+
+// WebhookEvent is a verified notification from a gateway about a payment. ID
+// is the provider's event id, which stays the same across redeliveries.
+type WebhookEvent struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	ProviderRef string `json:"provider_ref"`
+	Amount      int64  `json:"amount"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Processor moves payment intents through their states, calling the gateway
+// and recording every step, so an interrupted payment can be picked up again
+// from where it stopped.
+type Processor struct {
+	log     *jsonlog.Logger
+	gateway PaymentGateway
+	store   intentStore
+}
+
+// intentCapturer is implemented by gateways that record an intent as
+// captured themselves, atomically with taking the money.
+type intentCapturer interface {
+	CaptureIntent(ctx context.Context, intent data.PaymentIntent) error
+}
+
+type intentStore interface {
+	TransitionPaymentIntent(ctx context.Context, id int64, from, to, providerRef, reason string) error
+	RecordPaymentRefund(ctx context.Context, id int64, amount int64) (*data.PaymentIntent, error)
+}
+
+func NewProcessor(log *jsonlog.Logger, gateway PaymentGateway, store intentStore) *Processor {
+	return &Processor{
+		log:     log,
+		gateway: gateway,
+		store:   store,
+	}
+}
+
+// Collect authorizes and captures intent, updating it in place. An intent
+// with nothing to charge is captured without calling the gateway, and one
+// that is already captured is left alone. A failed step marks the intent
+// failed and returns the gateway's error.
+func (p *Processor) Collect(ctx context.Context, intent *data.PaymentIntent) error {
+	if intent.Status == data.PaymentCreated && intent.Amount == 0 {
+		return p.transition(ctx, intent, data.PaymentCaptured, "", "")
+	}
+
+	if intent.Status == data.PaymentCreated {
+		ref, err := p.gateway.Authorize(ctx, *intent)
+		if err != nil {
+			return p.fail(ctx, intent, err)
+		}
+		if err := p.transition(ctx, intent, data.PaymentAuthorized, ref, ""); err != nil {
+			return err
+		}
+	}
+
+	if intent.Status == data.PaymentAuthorized {
+		if err := p.capture(ctx, intent); err != nil {
+			return err
+		}
+	}
+
+	if intent.Status != data.PaymentCaptured {
+		return fmt.Errorf("payment intent %d is %s", intent.ID, intent.Status)
+	}
+	return nil
+}
+
+// Refund returns amount of a captured intent to the payer.
+func (p *Processor) Refund(ctx context.Context, intent *data.PaymentIntent, amount int64) error {
+	if intent.Status != data.PaymentCaptured || amount > intent.Amount-intent.RefundedAmount {
+		return ErrRefundExceedsCapture
+	}
+	if amount == 0 {
+		return nil
+	}
+	if intent.ProviderRef != "" {
+		if err := p.gateway.Refund(ctx, *intent, amount); err != nil {
+			return err
+		}
+	}
+
+	refunded, err := p.store.RecordPaymentRefund(ctx, intent.ID, amount)
+	if err != nil {
+		return err
+	}
+	*intent = *refunded
+
+	p.log.PrintInfo("payment refunded", map[string]string{
+		"method":    "payments.Refund",
+		"intent_id": strconv.FormatInt(intent.ID, 10),
+		"amount":    strconv.FormatInt(amount, 10),
+	})
+	return nil
+}
+
+// PayOut sends amount of intent back to the payer without recording it. The
+// caller must already have reserved the amount against the intent, so that
+// concurrent refunds cannot pay out more than was captured.
+func (p *Processor) PayOut(ctx context.Context, intent *data.PaymentIntent, amount int64) error {
+	if amount == 0 || intent.ProviderRef == "" {
+		return nil
+	}
+	if err := p.gateway.Refund(ctx, *intent, amount); err != nil {
+		return err
+	}
+
+	p.log.PrintInfo("payment refunded", map[string]string{
+		"method":    "payments.PayOut",
+		"intent_id": strconv.FormatInt(intent.ID, 10),
+		"amount":    strconv.FormatInt(amount, 10),
+	})
+	return nil
+}
+
+// capture captures an authorized intent at the gateway and records it.
+func (p *Processor) capture(ctx context.Context, intent *data.PaymentIntent) error {
+	capturer, ok := p.gateway.(intentCapturer)
+	if !ok {
+		if err := p.gateway.Capture(ctx, *intent); err != nil {
+			return p.fail(ctx, intent, err)
+		}
+		return p.transition(ctx, intent, data.PaymentCaptured, "", "")
+	}
+
+	if err := capturer.CaptureIntent(ctx, *intent); err != nil {
+		if errors.Is(err, ErrInsufficientBalance) {
+			return p.fail(ctx, intent, err)
+		}
+		return err
+	}
+	intent.Status = data.PaymentCaptured
+	intent.FailureReason = ""
+	intent.UpdatedAt = time.Now()
+	return nil
+}
+
+func (p *Processor) transition(ctx context.Context, intent *data.PaymentIntent, to, providerRef, reason string) error {
+	err := p.store.TransitionPaymentIntent(ctx, intent.ID, intent.Status, to, providerRef, reason)
+	if err != nil {
+		return err
+	}
+	intent.Status = to
+	if providerRef != "" {
+		intent.ProviderRef = providerRef
+	}
+	intent.FailureReason = reason
+	intent.UpdatedAt = time.Now()
+	return nil
+}
+
+func (p *Processor) fail(ctx context.Context, intent *data.PaymentIntent, cause error) error {
+	p.log.PrintInfo("payment failed", map[string]string{
+		"method":    "payments.Collect",
+		"intent_id": strconv.FormatInt(intent.ID, 10),
+		"reason":    cause.Error(),
+	})
+	if err := p.transition(ctx, intent, data.PaymentFailed, "", cause.Error()); err != nil {
+		return err
+	}
+	return cause
+}
@@ -0,0 +1,220 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"sync"
+	"testing"
+)
+
+// memoryStore keeps intents and wallets in memory, enforcing the same
+// transitions as the database.
+type memoryStore struct {
+	mu      sync.Mutex
+	intents map[int64]*data.PaymentIntent
+	wallets map[int64]int64
+}
+
+func newMemoryStore(intents ...data.PaymentIntent) *memoryStore {
+	s := &memoryStore{intents: map[int64]*data.PaymentIntent{}, wallets: map[int64]int64{}}
+	for i := range intents {
+		s.intents[intents[i].ID] = &intents[i]
+	}
+	return s
+}
+
+func (s *memoryStore) TransitionPaymentIntent(ctx context.Context, id int64, from, to, providerRef, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent := s.intents[id]
+	if !data.CanTransitionPayment(from, to) || intent.Status != from {
+		return errors.New("invalid payment intent transition")
+	}
+	intent.Status = to
+	if providerRef != "" {
+		intent.ProviderRef = providerRef
+	}
+	intent.FailureReason = reason
+	return nil
+}
+
+func (s *memoryStore) RecordPaymentRefund(ctx context.Context, id int64, amount int64) (*data.PaymentIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent := s.intents[id]
+	if intent.Status != data.PaymentCaptured || intent.RefundedAmount+amount > intent.Amount {
+		return nil, errors.New("invalid payment intent transition")
+	}
+	intent.RefundedAmount += amount
+	if intent.RefundedAmount == intent.Amount {
+		intent.Status = data.PaymentRefunded
+	}
+	refunded := *intent
+	return &refunded, nil
+}
+
+func (s *memoryStore) DebitWallet(ctx context.Context, userId int64, amount int64, currency string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.wallets[userId] < amount {
+		return false, nil
+	}
+	s.wallets[userId] -= amount
+	return true, nil
+}
+
+func (s *memoryStore) CreditWallet(ctx context.Context, userId int64, amount int64, currency string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.wallets[userId] += amount
+	return nil
+}
+
+func (s *memoryStore) CaptureWalletPayment(ctx context.Context, intentId int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent := s.intents[intentId]
+	if intent.Status != data.PaymentAuthorized {
+		return false, errors.New("invalid payment intent transition")
+	}
+	if s.wallets[intent.UserID] < intent.Amount {
+		return false, nil
+	}
+	s.wallets[intent.UserID] -= intent.Amount
+	intent.Status = data.PaymentCaptured
+	return true, nil
+}
+
+func newTestProcessor(gateway PaymentGateway, store *memoryStore) *Processor {
+	return NewProcessor(jsonlog.New(io.Discard, jsonlog.LevelOff), gateway, store)
+}
+
+func TestCollectWithFakeGateway(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     int64
+		script     func(g *FakeGateway)
+		wantErr    error
+		wantStatus string
+	}{
+		{name: "captured", amount: 1000, wantStatus: data.PaymentCaptured},
+		{name: "nothing to charge", amount: 0, wantStatus: data.PaymentCaptured},
+		{name: "declined", amount: 1000, script: func(g *FakeGateway) { g.DeclineAbove(500) }, wantErr: ErrDeclined, wantStatus: data.PaymentFailed},
+		{name: "capture fails", amount: 1000, script: func(g *FakeGateway) { g.FailNext(OpCapture, ErrDeclined) }, wantErr: ErrDeclined, wantStatus: data.PaymentFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewFakeGateway("secret")
+			if tt.script != nil {
+				tt.script(g)
+			}
+			store := newMemoryStore(data.PaymentIntent{ID: 1, Amount: tt.amount, Status: data.PaymentCreated})
+			intent := *store.intents[1]
+
+			err := newTestProcessor(g, store).Collect(context.Background(), &intent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Collect() = %v, want %v", err, tt.wantErr)
+			}
+			if intent.Status != tt.wantStatus || store.intents[1].Status != tt.wantStatus {
+				t.Errorf("status = %s, stored %s, want %s", intent.Status, store.intents[1].Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCollectWithWalletCapturesOnce(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     int64
+		wantErr     error
+		wantStatus  string
+		wantBalance int64
+	}{
+		{name: "covered", balance: 1500, wantStatus: data.PaymentCaptured, wantBalance: 500},
+		{name: "not covered", balance: 900, wantErr: ErrInsufficientBalance, wantStatus: data.PaymentFailed, wantBalance: 900},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore(data.PaymentIntent{ID: 1, UserID: 7, Amount: 1000, Status: data.PaymentCreated})
+			store.wallets[7] = tt.balance
+			intent := *store.intents[1]
+
+			err := newTestProcessor(NewWalletGateway(store), store).Collect(context.Background(), &intent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Collect() = %v, want %v", err, tt.wantErr)
+			}
+			if intent.Status != tt.wantStatus || store.intents[1].Status != tt.wantStatus {
+				t.Errorf("status = %s, stored %s, want %s", intent.Status, store.intents[1].Status, tt.wantStatus)
+			}
+			if store.wallets[7] != tt.wantBalance {
+				t.Errorf("balance = %d, want %d", store.wallets[7], tt.wantBalance)
+			}
+
+			// Collecting again leaves a captured intent and the wallet alone.
+			newTestProcessor(NewWalletGateway(store), store).Collect(context.Background(), &intent)
+			if store.wallets[7] != tt.wantBalance {
+				t.Errorf("balance after second Collect = %d, want %d", store.wallets[7], tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestRefund(t *testing.T) {
+	ctx := context.Background()
+	g := NewFakeGateway("secret")
+	store := newMemoryStore(data.PaymentIntent{ID: 1, Amount: 1000, Status: data.PaymentCreated})
+	p := newTestProcessor(g, store)
+
+	intent := *store.intents[1]
+	if err := p.Collect(ctx, &intent); err != nil {
+		t.Fatalf("Collect() = %v", err)
+	}
+
+	if err := p.Refund(ctx, &intent, 1001); !errors.Is(err, ErrRefundExceedsCapture) {
+		t.Errorf("Refund(1001) = %v, want %v", err, ErrRefundExceedsCapture)
+	}
+	if err := p.Refund(ctx, &intent, 400); err != nil {
+		t.Fatalf("Refund(400) = %v", err)
+	}
+	if intent.RefundedAmount != 400 || intent.Status != data.PaymentCaptured {
+		t.Errorf("after partial refund: refunded %d, status %s", intent.RefundedAmount, intent.Status)
+	}
+	if err := p.Refund(ctx, &intent, 600); err != nil {
+		t.Fatalf("Refund(600) = %v", err)
+	}
+	if intent.Status != data.PaymentRefunded {
+		t.Errorf("status after full refund = %s, want %s", intent.Status, data.PaymentRefunded)
+	}
+}
+
+func TestPayOutRecordsNothing(t *testing.T) {
+	ctx := context.Background()
+	g := NewFakeGateway("secret")
+	store := newMemoryStore(data.PaymentIntent{ID: 1, Amount: 1000, Status: data.PaymentCreated})
+	p := newTestProcessor(g, store)
+
+	intent := *store.intents[1]
+	if err := p.Collect(ctx, &intent); err != nil {
+		t.Fatalf("Collect() = %v", err)
+	}
+
+	if err := p.PayOut(ctx, &intent, 400); err != nil {
+		t.Fatalf("PayOut(400) = %v", err)
+	}
+	if store.intents[1].RefundedAmount != 0 {
+		t.Errorf("stored refunded amount = %d, want 0", store.intents[1].RefundedAmount)
+	}
+	if err := p.PayOut(ctx, &intent, 601); !errors.Is(err, ErrRefundExceedsCapture) {
+		t.Errorf("PayOut(601) = %v, want %v", err, ErrRefundExceedsCapture)
+	}
+}
@@ -0,0 +1,73 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries a webhook's signature as "t=<unix time>,v1=<hex
+// HMAC-SHA256 of "<unix time>.<body>">".
+const SignatureHeader = "Webhook-Signature"
+
+// SignatureTolerance is how far a webhook's timestamp may be from now before
+// it is rejected as a replay.
+const SignatureTolerance = 5 * time.Minute
+
+// Sign returns the SignatureHeader value for payload sent at t.
+func Sign(secret string, payload []byte, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, payload))
+}
+
+// VerifySignature checks the SignatureHeader of a webhook against payload and
+// rejects timestamps more than SignatureTolerance away from now. Nothing
+// verifies against an empty secret.
+func VerifySignature(secret string, payload []byte, header http.Header, now time.Time) error {
+	if secret == "" {
+		return ErrInvalidSignature
+	}
+
+	var ts string
+	var signatures [][]byte
+	for _, part := range strings.Split(header.Get(SignatureHeader), ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig, err := hex.DecodeString(value)
+			if err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	sent, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := mac(secret, ts, payload)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret, ts string, payload []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -0,0 +1,66 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"subscriptionMService/internal/data"
+)
+
+// ErrInsufficientBalance declines a wallet payment the balance does not cover.
+var ErrInsufficientBalance = errors.New("insufficient wallet balance")
+
+// WalletGateway charges the user's wallet balance kept by this service. It
+// holds nothing at authorization; the balance is checked and debited when
+// the payment is captured. Since the wallet lives next to the intents, the
+// processor captures through CaptureIntent, which records the capture in the
+// same transaction as the debit.
+type WalletGateway struct {
+	store walletStore
+}
+
+type walletStore interface {
+	DebitWallet(ctx context.Context, userId int64, amount int64, currency string) (bool, error)
+	CreditWallet(ctx context.Context, userId int64, amount int64, currency string) error
+	CaptureWalletPayment(ctx context.Context, intentId int64) (bool, error)
+}
+
+func NewWalletGateway(store walletStore) *WalletGateway {
+	return &WalletGateway{store: store}
+}
+
+func (g *WalletGateway) Authorize(ctx context.Context, intent data.PaymentIntent) (string, error) {
+	return "wallet_" + strconv.FormatInt(intent.ID, 10), nil
+}
+
+func (g *WalletGateway) Capture(ctx context.Context, intent data.PaymentIntent) error {
+	charged, err := g.store.DebitWallet(ctx, intent.UserID, intent.Amount, intent.Currency)
+	if err != nil {
+		return err
+	}
+	if !charged {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+// CaptureIntent debits the wallet and marks intent captured at once.
+func (g *WalletGateway) CaptureIntent(ctx context.Context, intent data.PaymentIntent) error {
+	charged, err := g.store.CaptureWalletPayment(ctx, intent.ID)
+	if err != nil {
+		return err
+	}
+	if !charged {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+func (g *WalletGateway) Refund(ctx context.Context, intent data.PaymentIntent, amount int64) error {
+	return g.store.CreditWallet(ctx, intent.UserID, amount, intent.Currency)
+}
+
+func (g *WalletGateway) VerifyWebhook(payload []byte, header http.Header) (*WebhookEvent, error) {
+	return nil, ErrWebhooksUnsupported
+}
@@ -8,37 +8,68 @@ import (
 )
 
 type PlanProvider interface {
-	ListPlans(ctx context.Context) []*subs.Plan
+	ListPlans(ctx context.Context, currency string) []*subs.Plan
+}
+
+// PlanSource is a PlanProvider that knows when the plans on sale change next.
+type PlanSource interface {
+	PlanProvider
+	NextPlanAvailabilityChange(ctx context.Context, now time.Time) (time.Time, error)
 }
 
 type CachedPlanProvider struct {
-	underlying PlanProvider
-	cache      []*subs.Plan
-	cacheTime  time.Time
+	underlying PlanSource
+	cache      map[string]cachedPlans
 	ttl        time.Duration
 	mu         sync.Mutex
 }
 
-func NewCachedPlanProvider(planProvider PlanProvider, ttl time.Duration) *CachedPlanProvider {
+// cachedPlans is the plan list priced in one currency.
+type cachedPlans struct {
+	plans     []*subs.Plan
+	cacheTime time.Time
+	expiresAt time.Time
+}
+
+func NewCachedPlanProvider(planProvider PlanSource, ttl time.Duration) *CachedPlanProvider {
 	return &CachedPlanProvider{
 		underlying: planProvider,
+		cache:      map[string]cachedPlans{},
 		ttl:        ttl,
 	}
 }
 
-func (c *CachedPlanProvider) ListPlans(ctx context.Context) []*subs.Plan {
+// ListPlans serves the cached plans for currency until the TTL runs out or a
+// plan goes on or off sale, whichever comes first.
+func (c *CachedPlanProvider) ListPlans(ctx context.Context, currency string) []*subs.Plan {
 	println("listplanCache")
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if time.Since(c.cacheTime) > c.ttl && c.cache != nil {
-		return c.cache
+	now := time.Now()
+	if cached, ok := c.cache[currency]; ok && now.Before(cached.expiresAt) {
+		return cached.plans
 	}
 
-	plans := c.underlying.ListPlans(ctx)
+	plans := c.underlying.ListPlans(ctx, currency)
 	if plans != nil {
-		c.cache = plans
+		cached := cachedPlans{plans: plans, cacheTime: now, expiresAt: now.Add(c.ttl)}
+		next, err := c.underlying.NextPlanAvailabilityChange(ctx, now)
+		if err != nil {
+			cached.expiresAt = now
+		} else if !next.IsZero() && next.Before(cached.expiresAt) {
+			cached.expiresAt = next
+		}
+		c.cache[currency] = cached
 	}
 	return plans
 
 }
+
+// Invalidate drops the cached plans so the next ListPlans reads them again.
+func (c *CachedPlanProvider) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cache = map[string]cachedPlans{}
+}
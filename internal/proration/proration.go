@@ -0,0 +1,70 @@
+package proration
+
+import (
+	"math"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/money"
+	"time"
+)
+
+// Policy decides how a move between plans is priced and when it takes effect.
+// Implementations hold the business rules so they can change without touching
+// storage code.
+type Policy interface {
+	Quote(sub data.Subscription, current, next data.Plan, now time.Time) data.PlanChange
+}
+
+// ProportionalPolicy applies upgrades immediately and schedules downgrades for
+// the end of the current period. An upgrade is a plan with a higher monthly
+// price, or the same monthly price and a higher rental limit. The upgrade
+// charge is the monthly price difference for the unused part of the period,
+// and the limit is topped up by the same share of the rental limit difference.
+type ProportionalPolicy struct{}
+
+func (ProportionalPolicy) Quote(sub data.Subscription, current, next data.Plan, now time.Time) data.PlanChange {
+	change := data.PlanChange{PlanID: next.ID}
+
+	if !isUpgrade(current, next) {
+		change.Kind = data.PlanChangeDowngrade
+		change.EffectiveAt = sub.ExpiresAt
+		return change
+	}
+
+	change.Kind = data.PlanChangeUpgrade
+	change.Immediate = true
+	change.EffectiveAt = now
+
+	unused := RemainingFraction(sub.PeriodStartedAt, sub.ExpiresAt, now)
+	priceDiff := monthlyPrice(next) - monthlyPrice(current)
+	limitDiff := float64(next.RentalLimit - current.RentalLimit)
+
+	change.Amount = max(0, money.Round(unused*float64(current.Duration)*priceDiff))
+	change.LimitTopUp = int32(math.Max(0, math.Round(unused*limitDiff)))
+	return change
+}
+
+// RemainingFraction returns the share of the period [start, end) left at now,
+// clamped to [0, 1].
+func RemainingFraction(start, end, now time.Time) float64 {
+	total := end.Sub(start)
+	if total <= 0 {
+		return 0
+	}
+	left := float64(end.Sub(now)) / float64(total)
+	return math.Min(1, math.Max(0, left))
+}
+
+func isUpgrade(current, next data.Plan) bool {
+	currentMonthly, nextMonthly := monthlyPrice(current), monthlyPrice(next)
+	if nextMonthly != currentMonthly {
+		return nextMonthly > currentMonthly
+	}
+	return next.RentalLimit > current.RentalLimit
+}
+
+func monthlyPrice(p data.Plan) float64 {
+	if p.Duration <= 0 {
+		return float64(p.Price)
+	}
+	return float64(p.Price) / float64(p.Duration)
+}
@@ -0,0 +1,95 @@
+package proration
+
+import (
+	"subscriptionMService/internal/data"
+	"testing"
+	"time"
+)
+
+func TestProportionalPolicyQuote(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := data.Subscription{PeriodStartedAt: start, ExpiresAt: start.AddDate(0, 0, 30)}
+	third := start.AddDate(0, 0, 10)
+
+	tests := []struct {
+		name    string
+		current data.Plan
+		next    data.Plan
+		now     time.Time
+		want    data.PlanChange
+	}{
+		{
+			name:    "upgrade charges the unused share",
+			current: data.Plan{ID: 1, Price: 3000, Duration: 1, RentalLimit: 10},
+			next:    data.Plan{ID: 2, Price: 6000, Duration: 1, RentalLimit: 40},
+			now:     third,
+			want:    data.PlanChange{PlanID: 2, Kind: data.PlanChangeUpgrade, Immediate: true, Amount: 2000, LimitTopUp: 20, EffectiveAt: third},
+		},
+		{
+			name:    "upgrade compares monthly prices",
+			current: data.Plan{ID: 1, Price: 9000, Duration: 3, RentalLimit: 10},
+			next:    data.Plan{ID: 2, Price: 12000, Duration: 3, RentalLimit: 10},
+			now:     third,
+			want:    data.PlanChange{PlanID: 2, Kind: data.PlanChangeUpgrade, Immediate: true, Amount: 2000, EffectiveAt: third},
+		},
+		{
+			name:    "same price with a higher limit is an upgrade",
+			current: data.Plan{ID: 1, Price: 3000, Duration: 1, RentalLimit: 10},
+			next:    data.Plan{ID: 2, Price: 3000, Duration: 1, RentalLimit: 16},
+			now:     third,
+			want:    data.PlanChange{PlanID: 2, Kind: data.PlanChangeUpgrade, Immediate: true, LimitTopUp: 4, EffectiveAt: third},
+		},
+		{
+			name:    "upgrade after the period charges nothing",
+			current: data.Plan{ID: 1, Price: 3000, Duration: 1, RentalLimit: 10},
+			next:    data.Plan{ID: 2, Price: 6000, Duration: 1, RentalLimit: 40},
+			now:     sub.ExpiresAt.Add(time.Hour),
+			want:    data.PlanChange{PlanID: 2, Kind: data.PlanChangeUpgrade, Immediate: true, EffectiveAt: sub.ExpiresAt.Add(time.Hour)},
+		},
+		{
+			name:    "cheaper plan is a downgrade at period end",
+			current: data.Plan{ID: 1, Price: 6000, Duration: 1, RentalLimit: 40},
+			next:    data.Plan{ID: 2, Price: 3000, Duration: 1, RentalLimit: 100},
+			now:     third,
+			want:    data.PlanChange{PlanID: 2, Kind: data.PlanChangeDowngrade, EffectiveAt: sub.ExpiresAt},
+		},
+		{
+			name:    "same plan terms are a downgrade",
+			current: data.Plan{ID: 1, Price: 3000, Duration: 1, RentalLimit: 10},
+			next:    data.Plan{ID: 2, Price: 3000, Duration: 1, RentalLimit: 10},
+			now:     third,
+			want:    data.PlanChange{PlanID: 2, Kind: data.PlanChangeDowngrade, EffectiveAt: sub.ExpiresAt},
+		},
+	}
+
+	for _, tt := range tests {
+		got := ProportionalPolicy{}.Quote(sub, tt.current, tt.next, tt.now)
+		if got != tt.want {
+			t.Errorf("%s: Quote() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRemainingFraction(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 30)
+
+	tests := []struct {
+		start, end, now time.Time
+		want            float64
+	}{
+		{start: start, end: end, now: start.AddDate(0, 0, 15), want: 0.5},
+		{start: start, end: end, now: start, want: 1},
+		{start: start, end: end, now: start.Add(-time.Hour), want: 1},
+		{start: start, end: end, now: end, want: 0},
+		{start: start, end: end, now: end.Add(time.Hour), want: 0},
+		{start: end, end: start, now: start, want: 0},
+		{start: start, end: start, now: start, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := RemainingFraction(tt.start, tt.end, tt.now); got != tt.want {
+			t.Errorf("RemainingFraction(%v, %v, %v) = %v, want %v", tt.start, tt.end, tt.now, got, tt.want)
+		}
+	}
+}
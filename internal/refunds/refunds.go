@@ -0,0 +1,53 @@
+package refunds
+
+import (
+	"math"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/money"
+	"subscriptionMService/internal/proration"
+	"time"
+)
+
+// Policy decides how much of a subscription's last payment is given back
+// when it is cancelled or refunded. Implementations hold the business rules
+// so they can change without touching storage code.
+type Policy interface {
+	Quote(sub data.Subscription, plan data.Plan, paid data.PaymentIntent, now time.Time) data.RefundQuote
+}
+
+// PlanPolicy applies the refund policy set on the subscription's plan. Only
+// what has not been refunded of the payment yet can be given back. A partial
+// refund pays back the share of the period that is unused, or the share of
+// the rental limit that is unused if that is smaller, so a user who has
+// already rented most of their allowance gets little back however early they
+// cancel.
+type PlanPolicy struct{}
+
+func (PlanPolicy) Quote(sub data.Subscription, plan data.Plan, paid data.PaymentIntent, now time.Time) data.RefundQuote {
+	quote := data.RefundQuote{
+		Policy:      plan.RefundPolicy,
+		Paid:        paid.Amount - paid.RefundedAmount,
+		UnusedTime:  proration.RemainingFraction(sub.PeriodStartedAt, sub.ExpiresAt, now),
+		UnusedLimit: UnusedLimit(sub, plan),
+	}
+
+	switch plan.RefundPolicy {
+	case data.RefundFull:
+		quote.Amount = quote.Paid
+	case data.RefundPartial:
+		share := math.Min(quote.UnusedTime, quote.UnusedLimit)
+		quote.Amount = min(quote.Paid, money.Round(share*float64(quote.Paid)))
+	}
+	return quote
+}
+
+// UnusedLimit returns the share of the plan's rental limit the subscription
+// has left, clamped to [0, 1]. Bonus limit on top of the plan's counts as
+// unused allowance only up to the plan's own limit.
+func UnusedLimit(sub data.Subscription, plan data.Plan) float64 {
+	if plan.RentalLimit <= 0 {
+		return 1
+	}
+	left := float64(sub.RemainingLimit) / float64(plan.RentalLimit)
+	return math.Min(1, math.Max(0, left))
+}
@@ -0,0 +1,75 @@
+package refunds
+
+import (
+	"subscriptionMService/internal/data"
+	"testing"
+	"time"
+)
+
+func TestPlanPolicyQuote(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 10)
+
+	tests := []struct {
+		name      string
+		policy    string
+		remaining int32
+		refunded  int64
+		want      int64
+	}{
+		{name: "none", policy: data.RefundNone, remaining: 8, want: 0},
+		{name: "full", policy: data.RefundFull, remaining: 8, want: 3000},
+		{name: "full after a partial refund", policy: data.RefundFull, remaining: 8, refunded: 1000, want: 2000},
+		{name: "partial by unused time", policy: data.RefundPartial, remaining: 8, want: 2000},
+		{name: "partial by unused limit", policy: data.RefundPartial, remaining: 2, want: 600},
+		{name: "partial with the limit used up", policy: data.RefundPartial, remaining: 0, want: 0},
+		{name: "partial after a partial refund", policy: data.RefundPartial, remaining: 8, refunded: 1000, want: 1333},
+	}
+
+	for _, tt := range tests {
+		sub := data.Subscription{PeriodStartedAt: start, ExpiresAt: start.AddDate(0, 0, 30), RemainingLimit: tt.remaining}
+		plan := data.Plan{RentalLimit: 10, RefundPolicy: tt.policy}
+		paid := data.PaymentIntent{Amount: 3000, RefundedAmount: tt.refunded}
+
+		quote := PlanPolicy{}.Quote(sub, plan, paid, now)
+		if quote.Amount != tt.want {
+			t.Errorf("%s: Quote().Amount = %d, want %d", tt.name, quote.Amount, tt.want)
+		}
+		if quote.Policy != tt.policy || quote.Paid != 3000-tt.refunded {
+			t.Errorf("%s: Quote() = %+v, want policy %s and paid %d", tt.name, quote, tt.policy, 3000-tt.refunded)
+		}
+	}
+}
+
+func TestPlanPolicyQuoteAfterPeriod(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := data.Subscription{PeriodStartedAt: start, ExpiresAt: start.AddDate(0, 0, 30), RemainingLimit: 10}
+	plan := data.Plan{RentalLimit: 10, RefundPolicy: data.RefundPartial}
+	paid := data.PaymentIntent{Amount: 3000}
+
+	quote := PlanPolicy{}.Quote(sub, plan, paid, sub.ExpiresAt.Add(time.Hour))
+	if quote.Amount != 0 || quote.UnusedTime != 0 {
+		t.Errorf("Quote() = %+v, want nothing back", quote)
+	}
+}
+
+func TestUnusedLimit(t *testing.T) {
+	tests := []struct {
+		limit     int32
+		remaining int32
+		want      float64
+	}{
+		{limit: 10, remaining: 5, want: 0.5},
+		{limit: 10, remaining: 0, want: 0},
+		{limit: 10, remaining: 15, want: 1},
+		{limit: 10, remaining: -3, want: 0},
+		{limit: 0, remaining: 0, want: 1},
+	}
+
+	for _, tt := range tests {
+		got := UnusedLimit(data.Subscription{RemainingLimit: tt.remaining}, data.Plan{RentalLimit: tt.limit})
+		if got != tt.want {
+			t.Errorf("UnusedLimit(remaining %d, limit %d) = %v, want %v", tt.remaining, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package retention
+
+import (
+	"slices"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// Policy decides which retention offer, if any, a subscriber gets when they
+// try to unsubscribe.
+type Policy interface {
+	Offer(sub data.Subscription, plan data.Plan, now time.Time) (data.RetentionOffer, bool)
+}
+
+// Rule matches subscribers by tenure and plan and describes the offer they
+// get. An empty PlanIDs matches every plan.
+type Rule struct {
+	Name            string
+	MinTenure       time.Duration
+	PlanIDs         []int32
+	Kind            string
+	DiscountPercent int64
+	BonusLimit      int32
+	FreeMonths      int32
+}
+
+// Rules is a Policy that makes the offer of the first matching rule, so more
+// generous rules should come first.
+type Rules []Rule
+
+func (rules Rules) Offer(sub data.Subscription, plan data.Plan, now time.Time) (data.RetentionOffer, bool) {
+	tenure := now.Sub(sub.CreatedAt)
+	for _, rule := range rules {
+		if tenure < rule.MinTenure {
+			continue
+		}
+		if len(rule.PlanIDs) > 0 && !slices.Contains(rule.PlanIDs, plan.ID) {
+			continue
+		}
+
+		offer := data.RetentionOffer{
+			SubscriptionID:  sub.ID,
+			UserID:          sub.UserID,
+			PlanID:          plan.ID,
+			Rule:            rule.Name,
+			Kind:            rule.Kind,
+			PeriodStartedAt: sub.PeriodStartedAt,
+		}
+		switch rule.Kind {
+		case data.RetentionDiscount:
+			offer.DiscountAmount = int64(plan.Price) * rule.DiscountPercent / 100
+		case data.RetentionBonusLimit:
+			offer.BonusLimit = rule.BonusLimit
+		case data.RetentionFreeMonth:
+			offer.FreeMonths = rule.FreeMonths
+		}
+		return offer, true
+	}
+	return data.RetentionOffer{}, false
+}
+
+// DefaultRules gives long-standing subscribers a free month, regulars a
+// discount on their next charge and everyone else some extra rentals.
+func DefaultRules() Rules {
+	return Rules{
+		{
+			Name:       "loyal-free-month",
+			MinTenure:  365 * 24 * time.Hour,
+			Kind:       data.RetentionFreeMonth,
+			FreeMonths: 1,
+		},
+		{
+			Name:            "regular-discount",
+			MinTenure:       90 * 24 * time.Hour,
+			Kind:            data.RetentionDiscount,
+			DiscountPercent: 30,
+		},
+		{
+			Name:       "newcomer-bonus",
+			Kind:       data.RetentionBonusLimit,
+			BonusLimit: 2,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package dunning
+
+import (
+	"context"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"time"
+)
+
+// Scheduler moves subscriptions whose grace period has run out to past due
+// and plans their payment retries. Retries are stored as dunning attempts, so
+// the schedule survives restarts; the renewal engine charges each one once it
+// is due. A subscription whose retries have all failed is expired here.
+type Scheduler struct {
+	log       *jsonlog.Logger
+	store     dunningStore
+	schedule  []time.Duration
+	batchSize int
+}
+
+type dunningStore interface {
+	BeginDunning(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error)
+	ScheduleDunning(ctx context.Context, now time.Time, schedule []time.Duration) (*data.DunningAttempt, error)
+}
+
+func New(log *jsonlog.Logger, store dunningStore, schedule []time.Duration, batchSize int) *Scheduler {
+	return &Scheduler{
+		log:       log,
+		store:     store,
+		schedule:  schedule,
+		batchSize: batchSize,
+	}
+}
+
+// ScheduleDue moves up to batchSize subscriptions out of grace, then
+// schedules the next retry of up to batchSize past due subscriptions.
+func (s *Scheduler) ScheduleDue(ctx context.Context) {
+	pastDue, err := s.store.BeginDunning(ctx, time.Now(), s.batchSize)
+	if err != nil && ctx.Err() == nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "dunning.ScheduleDue",
+		})
+	}
+	for _, sub := range pastDue {
+		s.log.PrintInfo("grace period over, subscription past due", map[string]string{
+			"method":          "dunning.ScheduleDue",
+			"subscription_id": fmt.Sprint(sub.ID),
+			"user_id":         fmt.Sprint(sub.UserID),
+			"expires_at":      sub.ExpiresAt.Format(time.RFC3339),
+		})
+	}
+
+	for i := 0; i < s.batchSize && ctx.Err() == nil; i++ {
+		attempt, err := s.store.ScheduleDunning(ctx, time.Now(), s.schedule)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.log.PrintError(err, map[string]string{
+					"method": "dunning.ScheduleDue",
+				})
+			}
+			return
+		}
+		if attempt == nil {
+			return
+		}
+
+		props := map[string]string{
+			"method":          "dunning.ScheduleDue",
+			"subscription_id": fmt.Sprint(attempt.SubscriptionID),
+			"user_id":         fmt.Sprint(attempt.UserID),
+			"attempt":         fmt.Sprint(attempt.Attempt),
+		}
+		if attempt.Status == data.DunningExhausted {
+			s.log.PrintInfo("payment retries exhausted, subscription expired", props)
+			continue
+		}
+		props["scheduled_at"] = attempt.ScheduledAt.Format(time.RFC3339)
+		s.log.PrintInfo("payment retry scheduled", props)
+	}
+}
@@ -0,0 +1,269 @@
+package dunning
+
+import (
+	"context"
+	"io"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySub is a subscription together with the columns dunning tracks on it.
+type memorySub struct {
+	data.Subscription
+	graceUntil   time.Time
+	pastDueSince time.Time
+}
+
+// memoryStore keeps subscriptions and their dunning attempts in memory,
+// starting and scheduling dunning the way the database does. It survives a
+// restart of the scheduler by being handed to a new one.
+type memoryStore struct {
+	mu       sync.Mutex
+	subs     map[int64]*memorySub
+	attempts []*data.DunningAttempt
+}
+
+func newMemoryStore(subs ...*memorySub) *memoryStore {
+	s := &memoryStore{subs: map[int64]*memorySub{}}
+	for _, sub := range subs {
+		s.subs[sub.ID] = sub
+	}
+	return s
+}
+
+func (s *memoryStore) BeginDunning(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pastDue []data.Subscription
+	for _, sub := range s.subs {
+		if len(pastDue) == limit || sub.Status != "grace" || sub.graceUntil.After(now) {
+			continue
+		}
+		sub.Status = "past_due"
+		sub.graceUntil = time.Time{}
+		sub.pastDueSince = sub.ExpiresAt
+		s.attempts = append(s.attempts, &data.DunningAttempt{
+			SubscriptionID: sub.ID,
+			UserID:         sub.UserID,
+			PastDueSince:   sub.pastDueSince,
+			Status:         data.DunningFailed,
+			ScheduledAt:    sub.pastDueSince,
+			AttemptedAt:    now,
+		})
+		pastDue = append(pastDue, sub.Subscription)
+	}
+	return pastDue, nil
+}
+
+func (s *memoryStore) ScheduleDunning(ctx context.Context, now time.Time, schedule []time.Duration) (*data.DunningAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.subs {
+		if sub.Status != "past_due" {
+			continue
+		}
+		cycle := s.cycle(sub)
+		pending := false
+		for _, a := range cycle {
+			pending = pending || a.Status == data.DunningScheduled
+		}
+		if pending {
+			continue
+		}
+
+		a := &data.DunningAttempt{
+			SubscriptionID: sub.ID,
+			UserID:         sub.UserID,
+			PastDueSince:   sub.pastDueSince,
+			Attempt:        int32(len(cycle)),
+			Status:         data.DunningScheduled,
+			ScheduledAt:    now,
+		}
+		if len(cycle) > len(schedule) {
+			a.Status = data.DunningExhausted
+			a.AttemptedAt = now
+			sub.Status = "expired"
+			sub.pastDueSince = time.Time{}
+		} else if due := a.PastDueSince.Add(schedule[a.Attempt-1]); due.After(now) {
+			a.ScheduledAt = due
+		}
+		s.attempts = append(s.attempts, a)
+		scheduled := *a
+		return &scheduled, nil
+	}
+	return nil, nil
+}
+
+// cycle returns the attempts of the subscription's current dunning cycle.
+func (s *memoryStore) cycle(sub *memorySub) []*data.DunningAttempt {
+	var cycle []*data.DunningAttempt
+	for _, a := range s.attempts {
+		if a.SubscriptionID == sub.ID && a.PastDueSince.Equal(sub.pastDueSince) {
+			cycle = append(cycle, a)
+		}
+	}
+	return cycle
+}
+
+// fail settles the pending retry of sub as failed, as a declined renewal
+// charge does.
+func (s *memoryStore) fail(sub *memorySub, now time.Time) {
+	for _, a := range s.cycle(sub) {
+		if a.Status == data.DunningScheduled {
+			a.Status = data.DunningFailed
+			a.AttemptedAt = now
+		}
+	}
+}
+
+func newTestScheduler(store *memoryStore, schedule []time.Duration) *Scheduler {
+	return New(jsonlog.New(io.Discard, jsonlog.LevelOff), store, schedule, 10)
+}
+
+func TestScheduleDue(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	schedule := []time.Duration{day, 3 * day}
+
+	tests := []struct {
+		name          string
+		sub           memorySub
+		failed        int
+		noRetries     bool
+		wantStatus    string
+		wantAttempts  int
+		wantScheduled time.Time
+		wantLast      string
+	}{
+		{
+			name:       "grace not over",
+			sub:        memorySub{Subscription: data.Subscription{Status: "grace", ExpiresAt: now.Add(-day)}, graceUntil: now.Add(day)},
+			wantStatus: "grace",
+		},
+		{
+			name:          "grace over",
+			sub:           memorySub{Subscription: data.Subscription{Status: "grace", ExpiresAt: now.Add(-12 * time.Hour)}, graceUntil: now.Add(-time.Minute)},
+			wantStatus:    "past_due",
+			wantAttempts:  2,
+			wantScheduled: now.Add(12 * time.Hour),
+			wantLast:      data.DunningScheduled,
+		},
+		{
+			name:          "retry overdue",
+			sub:           memorySub{Subscription: data.Subscription{Status: "past_due", ExpiresAt: now.Add(-2 * day)}, pastDueSince: now.Add(-2 * day)},
+			failed:        1,
+			wantStatus:    "past_due",
+			wantAttempts:  2,
+			wantScheduled: now,
+			wantLast:      data.DunningScheduled,
+		},
+		{
+			name:          "second retry",
+			sub:           memorySub{Subscription: data.Subscription{Status: "past_due", ExpiresAt: now.Add(-2 * day)}, pastDueSince: now.Add(-2 * day)},
+			failed:        2,
+			wantStatus:    "past_due",
+			wantAttempts:  3,
+			wantScheduled: now.Add(day),
+			wantLast:      data.DunningScheduled,
+		},
+		{
+			name:         "schedule exhausted",
+			sub:          memorySub{Subscription: data.Subscription{Status: "past_due", ExpiresAt: now.Add(-4 * day)}, pastDueSince: now.Add(-4 * day)},
+			failed:       3,
+			wantStatus:   "expired",
+			wantAttempts: 4,
+			wantLast:     data.DunningExhausted,
+		},
+		{
+			name:         "no retries configured",
+			sub:          memorySub{Subscription: data.Subscription{Status: "grace", ExpiresAt: now.Add(-day)}, graceUntil: now.Add(-time.Minute)},
+			noRetries:    true,
+			wantStatus:   "expired",
+			wantAttempts: 2,
+			wantLast:     data.DunningExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := tt.sub
+			sub.ID, sub.UserID = 1, 7
+			store := newMemoryStore(&sub)
+			for i := 0; i < tt.failed; i++ {
+				store.attempts = append(store.attempts, &data.DunningAttempt{
+					SubscriptionID: sub.ID,
+					PastDueSince:   sub.pastDueSince,
+					Attempt:        int32(i),
+					Status:         data.DunningFailed,
+				})
+			}
+			steps := schedule
+			if tt.noRetries {
+				steps = nil
+			}
+
+			newTestScheduler(store, steps).ScheduleDue(context.Background())
+
+			if sub.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", sub.Status, tt.wantStatus)
+			}
+			if len(store.attempts) != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", len(store.attempts), tt.wantAttempts)
+			}
+			if tt.wantAttempts == 0 {
+				return
+			}
+			last := store.attempts[len(store.attempts)-1]
+			if last.Status != tt.wantLast {
+				t.Errorf("last attempt = %q, want %q", last.Status, tt.wantLast)
+			}
+			if tt.wantLast == data.DunningScheduled && last.ScheduledAt.Sub(tt.wantScheduled).Abs() > time.Second {
+				t.Errorf("retry scheduled at %s, want %s", last.ScheduledAt, tt.wantScheduled)
+			}
+		})
+	}
+}
+
+func TestScheduleDueAfterRestart(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	schedule := []time.Duration{day, 3 * day}
+
+	sub := &memorySub{
+		Subscription: data.Subscription{ID: 1, UserID: 7, Status: "grace", ExpiresAt: now.Add(-2 * day)},
+		graceUntil:   now.Add(-day),
+	}
+	store := newMemoryStore(sub)
+
+	newTestScheduler(store, schedule).ScheduleDue(context.Background())
+	if len(store.attempts) != 2 || store.attempts[1].Status != data.DunningScheduled {
+		t.Fatalf("attempts after first run = %d, want the failed charge and a scheduled retry", len(store.attempts))
+	}
+
+	// A new scheduler finds the pending retry in the store and leaves it for
+	// the renewal engine rather than scheduling another.
+	restarted := newTestScheduler(store, schedule)
+	restarted.ScheduleDue(context.Background())
+	if len(store.attempts) != 2 {
+		t.Fatalf("attempts after restart = %d, want 2", len(store.attempts))
+	}
+
+	// Once the retry fails, the next one is scheduled from the same cycle,
+	// and after that the schedule runs out.
+	store.fail(sub, now)
+	restarted.ScheduleDue(context.Background())
+	if len(store.attempts) != 3 || !store.attempts[2].ScheduledAt.Equal(sub.pastDueSince.Add(3*day)) {
+		t.Fatalf("second retry = %+v, want it 3 days after the period ended", store.attempts[len(store.attempts)-1])
+	}
+
+	store.fail(sub, now)
+	newTestScheduler(store, schedule).ScheduleDue(context.Background())
+	if sub.Status != "expired" || store.attempts[3].Status != data.DunningExhausted {
+		t.Errorf("status = %q, last attempt %q, want expired and %q", sub.Status, store.attempts[3].Status, data.DunningExhausted)
+	}
+}
@@ -0,0 +1,57 @@
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"time"
+)
+
+// Expirer moves subscriptions past their expires_at into the expired status.
+type Expirer struct {
+	log        *jsonlog.Logger
+	subExpirer subExpirer
+	batchSize  int
+}
+
+type subExpirer interface {
+	ExpireSubscriptions(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error)
+}
+
+func New(log *jsonlog.Logger, subExpirer subExpirer, batchSize int) *Expirer {
+	return &Expirer{
+		log:        log,
+		subExpirer: subExpirer,
+		batchSize:  batchSize,
+	}
+}
+
+// ExpireDue expires every subscription that is due, batch by batch.
+func (e *Expirer) ExpireDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		expired, err := e.subExpirer.ExpireSubscriptions(ctx, time.Now(), e.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				e.log.PrintError(err, map[string]string{
+					"method": "expiry.ExpireDue",
+				})
+			}
+			return
+		}
+
+		for _, sub := range expired {
+			e.log.PrintInfo("subscription expired", map[string]string{
+				"method":          "expiry.ExpireDue",
+				"subscription_id": fmt.Sprint(sub.ID),
+				"user_id":         fmt.Sprint(sub.UserID),
+				"plan_id":         fmt.Sprint(sub.PlanID),
+				"expires_at":      sub.ExpiresAt.Format(time.RFC3339),
+			})
+		}
+
+		if len(expired) < e.batchSize {
+			return
+		}
+	}
+}
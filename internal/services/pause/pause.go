@@ -0,0 +1,57 @@
+package pause
+
+import (
+	"context"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"time"
+)
+
+// Resumer automatically resumes subscriptions paused for longer than their
+// plan's max_pause_days.
+type Resumer struct {
+	log        *jsonlog.Logger
+	subResumer subResumer
+	batchSize  int
+}
+
+type subResumer interface {
+	ResumeOverduePauses(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error)
+}
+
+func New(log *jsonlog.Logger, subResumer subResumer, batchSize int) *Resumer {
+	return &Resumer{
+		log:        log,
+		subResumer: subResumer,
+		batchSize:  batchSize,
+	}
+}
+
+// ResumeOverdue resumes every overdue pause, batch by batch.
+func (r *Resumer) ResumeOverdue(ctx context.Context) {
+	for ctx.Err() == nil {
+		resumed, err := r.subResumer.ResumeOverduePauses(ctx, time.Now(), r.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.log.PrintError(err, map[string]string{
+					"method": "pause.ResumeOverdue",
+				})
+			}
+			return
+		}
+
+		for _, sub := range resumed {
+			r.log.PrintInfo("subscription pause limit reached, resumed", map[string]string{
+				"method":          "pause.ResumeOverdue",
+				"subscription_id": fmt.Sprint(sub.ID),
+				"user_id":         fmt.Sprint(sub.UserID),
+				"expires_at":      sub.ExpiresAt.Format(time.RFC3339),
+			})
+		}
+
+		if len(resumed) < r.batchSize {
+			return
+		}
+	}
+}
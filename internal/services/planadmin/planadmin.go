@@ -0,0 +1,284 @@
+package planadmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"subscriptionMService/internal/contextkeys"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/internal/money"
+	"subscriptionMService/internal/validator"
+	"subscriptionMService/storage/postgres"
+)
+
+// PlanAdmin manages the plan catalogue. Every method requires the admin role,
+// and every write drops the cached plan list so customers see it at once.
+type PlanAdmin struct {
+	log   *jsonlog.Logger
+	store planStore
+	cache cacheInvalidator
+}
+
+type planStore interface {
+	CreatePlan(ctx context.Context, plan *data.Plan) error
+	UpdatePlan(ctx context.Context, plan *data.Plan) (*data.Plan, bool, error)
+	ArchivePlan(ctx context.Context, planId int32) error
+	GetPlan(ctx context.Context, planId int32) (*data.Plan, error)
+	SetPlanGrandfathered(ctx context.Context, planId int32, grandfathered bool) error
+	SetPlanRefundPolicy(ctx context.Context, planId int32, policy string) error
+	SetPlanAvailability(ctx context.Context, plan *data.Plan) (*data.Plan, error)
+	SetPlanPrice(ctx context.Context, planId int32, currency string, amount int32) (*data.Plan, bool, error)
+}
+
+type cacheInvalidator interface {
+	Invalidate()
+}
+
+func New(log *jsonlog.Logger, store planStore, cache cacheInvalidator) *PlanAdmin {
+	return &PlanAdmin{
+		log:   log,
+		store: store,
+		cache: cache,
+	}
+}
+
+// CreatePlan validates and stores a new plan, filling in its id and version.
+func (a *PlanAdmin) CreatePlan(ctx context.Context, plan *data.Plan) error {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if plan.Visibility == "" {
+		plan.Visibility = data.PlanPublic
+	}
+	if plan.RefundPolicy == "" {
+		plan.RefundPolicy = data.RefundNone
+	}
+	v := validator.New()
+	if data.ValidatePlan(v, plan); !v.Valid() {
+		return status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	if err := a.store.CreatePlan(ctx, plan); err != nil {
+		a.log.PrintError(err, map[string]string{
+			"method": "planadmin.CreatePlan",
+		})
+		return status.Error(codes.Internal, "Internal error")
+	}
+	a.cache.Invalidate()
+
+	a.log.PrintInfo("plan created", map[string]string{
+		"method":  "planadmin.CreatePlan",
+		"plan_id": strconv.Itoa(int(plan.ID)),
+	})
+	return nil
+}
+
+// UpdatePlan changes the plan with plan.ID. A plan with subscribers is not
+// edited: a new version is created and the old one archived, so the returned
+// plan may have a different id.
+func (a *PlanAdmin) UpdatePlan(ctx context.Context, plan *data.Plan) (*data.Plan, error) {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Availability and the refund policy are changed with SetAvailability
+	// and SetRefundPolicy and ignored here.
+	plan.Visibility = data.PlanPublic
+	plan.RefundPolicy = data.RefundNone
+	v := validator.New()
+	if data.ValidatePlan(v, plan); !v.Valid() {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	updated, versioned, err := a.store.UpdatePlan(ctx, plan)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPlanNotFound) {
+			return nil, status.Error(codes.NotFound, "Plan not found")
+		}
+		a.log.PrintError(err, map[string]string{
+			"method": "planadmin.UpdatePlan",
+		})
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	a.cache.Invalidate()
+
+	a.log.PrintInfo("plan updated", map[string]string{
+		"method":    "planadmin.UpdatePlan",
+		"plan_id":   strconv.Itoa(int(updated.ID)),
+		"version":   strconv.Itoa(int(updated.Version)),
+		"versioned": strconv.FormatBool(versioned),
+	})
+	return updated, nil
+}
+
+// SetPrice sets the price of plan planId in currency, in minor units. Prices
+// in currencies that live subscribers pay in are versioned like UpdatePlan, so
+// the returned plan may have a different id.
+func (a *PlanAdmin) SetPrice(ctx context.Context, planId int32, currency string, amount int32) (*data.Plan, error) {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	v := validator.New()
+	v.Check(money.Supported(currency), "currency", "must be a supported currency code")
+	v.Check(amount > 0, "amount", "must be greater than zero")
+	if !v.Valid() {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	updated, versioned, err := a.store.SetPlanPrice(ctx, planId, currency, amount)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPlanNotFound) {
+			return nil, status.Error(codes.NotFound, "Plan not found")
+		}
+		a.log.PrintError(err, map[string]string{
+			"method": "planadmin.SetPrice",
+		})
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	a.cache.Invalidate()
+
+	a.log.PrintInfo("plan price set", map[string]string{
+		"method":    "planadmin.SetPrice",
+		"plan_id":   strconv.Itoa(int(updated.ID)),
+		"price":     money.Format(int64(amount), currency),
+		"versioned": strconv.FormatBool(versioned),
+	})
+	return updated, nil
+}
+
+// SetAvailability changes plan.ID's visibility, availability window and sort
+// order in place.
+func (a *PlanAdmin) SetAvailability(ctx context.Context, plan *data.Plan) (*data.Plan, error) {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	v := validator.New()
+	if data.ValidatePlanAvailability(v, plan); !v.Valid() {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	updated, err := a.store.SetPlanAvailability(ctx, plan)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPlanNotFound) {
+			return nil, status.Error(codes.NotFound, "Plan not found")
+		}
+		a.log.PrintError(err, map[string]string{
+			"method": "planadmin.SetAvailability",
+		})
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	a.cache.Invalidate()
+
+	a.log.PrintInfo("plan availability changed", map[string]string{
+		"method":     "planadmin.SetAvailability",
+		"plan_id":    strconv.Itoa(int(updated.ID)),
+		"visibility": updated.Visibility,
+	})
+	return updated, nil
+}
+
+// ArchivePlan takes a plan off sale. Existing subscribers keep it until they
+// change plan or their subscription ends.
+func (a *PlanAdmin) ArchivePlan(ctx context.Context, planId int32) error {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if err := a.store.ArchivePlan(ctx, planId); err != nil {
+		if errors.Is(err, postgres.ErrPlanNotFound) {
+			return status.Error(codes.NotFound, "Plan not found")
+		}
+		a.log.PrintError(err, map[string]string{
+			"method": "planadmin.ArchivePlan",
+		})
+		return status.Error(codes.Internal, "Internal error")
+	}
+	a.cache.Invalidate()
+
+	a.log.PrintInfo("plan archived", map[string]string{
+		"method":  "planadmin.ArchivePlan",
+		"plan_id": strconv.Itoa(int(planId)),
+	})
+	return nil
+}
+
+// SetGrandfathered decides whether subscribers of a plan version keep it after
+// it is superseded, or move to the family's current version when they renew.
+func (a *PlanAdmin) SetGrandfathered(ctx context.Context, planId int32, grandfathered bool) error {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if err := a.store.SetPlanGrandfathered(ctx, planId, grandfathered); err != nil {
+		if errors.Is(err, postgres.ErrPlanNotFound) {
+			return status.Error(codes.NotFound, "Plan not found")
+		}
+		a.log.PrintError(err, map[string]string{
+			"method": "planadmin.SetGrandfathered",
+		})
+		return status.Error(codes.Internal, "Internal error")
+	}
+
+	a.log.PrintInfo("plan grandfathering changed", map[string]string{
+		"method":        "planadmin.SetGrandfathered",
+		"plan_id":       strconv.Itoa(int(planId)),
+		"grandfathered": strconv.FormatBool(grandfathered),
+	})
+	return nil
+}
+
+// SetRefundPolicy sets whether subscribers of a plan version get nothing, the
+// unused share or all of their last payment back when they cancel.
+func (a *PlanAdmin) SetRefundPolicy(ctx context.Context, planId int32, policy string) error {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	v := validator.New()
+	if data.ValidateRefundPolicy(v, policy); !v.Valid() {
+		return status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	if err := a.store.SetPlanRefundPolicy(ctx, planId, policy); err != nil {
+		if errors.Is(err, postgres.ErrPlanNotFound) {
+			return status.Error(codes.NotFound, "Plan not found")
+		}
+		a.log.PrintError(err, map[string]string{
+			"method": "planadmin.SetRefundPolicy",
+		})
+		return status.Error(codes.Internal, "Internal error")
+	}
+
+	a.log.PrintInfo("plan refund policy changed", map[string]string{
+		"method":        "planadmin.SetRefundPolicy",
+		"plan_id":       strconv.Itoa(int(planId)),
+		"refund_policy": policy,
+	})
+	return nil
+}
+
+// GetPlan returns any plan by id, including archived versions.
+func (a *PlanAdmin) GetPlan(ctx context.Context, planId int32) (*data.Plan, error) {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	plan, err := a.store.GetPlan(ctx, planId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPlanNotFound) {
+			return nil, status.Error(codes.NotFound, "Plan not found")
+		}
+		a.log.PrintError(err, map[string]string{
+			"method": "planadmin.GetPlan",
+		})
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	return plan, nil
+}
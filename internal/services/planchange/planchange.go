@@ -0,0 +1,57 @@
+package planchange
+
+import (
+	"context"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"time"
+)
+
+// Applier switches subscriptions onto their scheduled plan once the change is
+// due and the renewal engine has not already rolled them over.
+type Applier struct {
+	log         *jsonlog.Logger
+	planChanger planChanger
+	batchSize   int
+}
+
+type planChanger interface {
+	ApplyDuePlanChanges(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error)
+}
+
+func New(log *jsonlog.Logger, planChanger planChanger, batchSize int) *Applier {
+	return &Applier{
+		log:         log,
+		planChanger: planChanger,
+		batchSize:   batchSize,
+	}
+}
+
+// ApplyDue applies every due plan change, batch by batch.
+func (a *Applier) ApplyDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		applied, err := a.planChanger.ApplyDuePlanChanges(ctx, time.Now(), a.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.log.PrintError(err, map[string]string{
+					"method": "planchange.ApplyDue",
+				})
+			}
+			return
+		}
+
+		for _, sub := range applied {
+			a.log.PrintInfo("scheduled plan change applied", map[string]string{
+				"method":          "planchange.ApplyDue",
+				"subscription_id": fmt.Sprint(sub.ID),
+				"user_id":         fmt.Sprint(sub.UserID),
+				"plan_id":         fmt.Sprint(sub.PlanID),
+			})
+		}
+
+		if len(applied) < a.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/storage/postgres"
+	"time"
+)
+
+// Reconciler repairs subscriptions left pending by a subscribe that never
+// finished, for example because the process stopped between inserting the
+// row and provisioning the bucket. Bucket calls need the user's token, so
+// stale subscriptions are compensated rather than retried; the user can
+// subscribe again. A payment captured for them is refunded first.
+type Reconciler struct {
+	log        *jsonlog.Logger
+	store      pendingStore
+	payments   paymentRefunder
+	staleAfter time.Duration
+	batchSize  int
+}
+
+type pendingStore interface {
+	ListStalePendingSubscriptions(ctx context.Context, before time.Time, limit int) ([]data.Subscription, error)
+	FailPendingSubscription(ctx context.Context, subId int64, reason string) error
+	GetOpenPaymentIntent(ctx context.Context, subId int64, purpose string) (*data.PaymentIntent, error)
+}
+
+type paymentRefunder interface {
+	Refund(ctx context.Context, intent *data.PaymentIntent, amount int64) error
+}
+
+func New(log *jsonlog.Logger, store pendingStore, payments paymentRefunder, staleAfter time.Duration, batchSize int) *Reconciler {
+	return &Reconciler{
+		log:        log,
+		store:      store,
+		payments:   payments,
+		staleAfter: staleAfter,
+		batchSize:  batchSize,
+	}
+}
+
+// RepairStale compensates every subscription that has been pending for longer
+// than staleAfter, batch by batch.
+func (r *Reconciler) RepairStale(ctx context.Context) {
+	for ctx.Err() == nil {
+		stale, err := r.store.ListStalePendingSubscriptions(ctx, time.Now().Add(-r.staleAfter), r.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.log.PrintError(err, map[string]string{
+					"method": "provisioning.RepairStale",
+				})
+			}
+			return
+		}
+
+		for _, sub := range stale {
+			err := r.refundCaptured(ctx, sub.ID)
+			if err == nil {
+				err = r.store.FailPendingSubscription(ctx, sub.ID, "provisioning did not finish")
+			}
+			if err != nil {
+				r.log.PrintError(err, map[string]string{
+					"method":          "provisioning.RepairStale",
+					"subscription_id": fmt.Sprint(sub.ID),
+				})
+				continue
+			}
+			r.log.PrintInfo("stale pending subscription compensated", map[string]string{
+				"method":          "provisioning.RepairStale",
+				"subscription_id": fmt.Sprint(sub.ID),
+				"user_id":         fmt.Sprint(sub.UserID),
+				"plan_id":         fmt.Sprint(sub.PlanID),
+			})
+		}
+
+		if len(stale) < r.batchSize {
+			return
+		}
+	}
+}
+
+// refundCaptured refunds the subscribe payment of a pending subscription if it
+// was captured before provisioning stopped.
+func (r *Reconciler) refundCaptured(ctx context.Context, subId int64) error {
+	intent, err := r.store.GetOpenPaymentIntent(ctx, subId, data.PaymentSubscribe)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPaymentIntentNotFound) {
+			return nil
+		}
+		return err
+	}
+	if intent.Status != data.PaymentCaptured {
+		return nil
+	}
+	return r.payments.Refund(ctx, intent, intent.Amount-intent.RefundedAmount)
+}
@@ -0,0 +1,103 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/internal/outbox"
+	"time"
+)
+
+// Relay publishes subscription events from the outbox, retrying failed
+// deliveries with exponential backoff.
+type Relay struct {
+	log        *jsonlog.Logger
+	store      outboxStore
+	publisher  outbox.Publisher
+	batchSize  int
+	lease      time.Duration
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+type outboxStore interface {
+	ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]data.OutboxMessage, error)
+	MarkOutboxPublished(ctx context.Context, id int64) error
+	MarkOutboxFailed(ctx context.Context, id int64, retryAt time.Time, reason string) error
+}
+
+func New(log *jsonlog.Logger, store outboxStore, publisher outbox.Publisher, batchSize int, lease, minBackoff, maxBackoff time.Duration) *Relay {
+	return &Relay{
+		log:        log,
+		store:      store,
+		publisher:  publisher,
+		batchSize:  batchSize,
+		lease:      lease,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+	}
+}
+
+// PublishDue publishes every message that is due, batch by batch.
+func (r *Relay) PublishDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := r.store.ClaimOutboxMessages(ctx, time.Now(), r.lease, r.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.log.PrintError(err, map[string]string{
+					"method": "relay.PublishDue",
+				})
+			}
+			return
+		}
+
+		for _, msg := range messages {
+			r.publish(ctx, msg)
+		}
+
+		if len(messages) < r.batchSize {
+			return
+		}
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, msg data.OutboxMessage) {
+	err := r.publisher.Publish(ctx, msg)
+	if err == nil {
+		err = r.store.MarkOutboxPublished(ctx, msg.ID)
+		if err != nil {
+			r.log.PrintError(err, map[string]string{
+				"method":   "relay.publish",
+				"event_id": fmt.Sprint(msg.EventID),
+			})
+		}
+		return
+	}
+
+	retryAt := time.Now().Add(r.backoff(msg.Attempts))
+	r.log.PrintError(err, map[string]string{
+		"method":     "relay.publish",
+		"event_id":   fmt.Sprint(msg.EventID),
+		"event_type": msg.EventType,
+		"attempts":   fmt.Sprint(msg.Attempts),
+		"retry_at":   retryAt.Format(time.RFC3339),
+	})
+	err = r.store.MarkOutboxFailed(ctx, msg.ID, retryAt, err.Error())
+	if err != nil {
+		r.log.PrintError(err, map[string]string{
+			"method":   "relay.publish",
+			"event_id": fmt.Sprint(msg.EventID),
+		})
+	}
+}
+
+// backoff doubles the delay with every attempt, from minBackoff up to
+// maxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.minBackoff
+	for i := 1; i < attempts && delay < r.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.maxBackoff)
+}
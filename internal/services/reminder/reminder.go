@@ -0,0 +1,56 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"time"
+)
+
+// Scheduler queues expiry and low balance reminders. The reminders go out
+// through the outbox, where the notifications dispatcher picks them up.
+type Scheduler struct {
+	log       *jsonlog.Logger
+	queuer    reminderQueuer
+	batchSize int
+}
+
+type reminderQueuer interface {
+	QueueReminders(ctx context.Context, now time.Time, limit int) ([]data.Reminder, error)
+}
+
+func New(log *jsonlog.Logger, queuer reminderQueuer, batchSize int) *Scheduler {
+	return &Scheduler{
+		log:       log,
+		queuer:    queuer,
+		batchSize: batchSize,
+	}
+}
+
+// QueueDue queues every reminder that is due, batch by batch.
+func (s *Scheduler) QueueDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		reminders, err := s.queuer.QueueReminders(ctx, time.Now(), s.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.log.PrintError(err, map[string]string{
+					"method": "reminder.QueueDue",
+				})
+			}
+			return
+		}
+
+		for _, reminder := range reminders {
+			s.log.PrintInfo("reminder queued", map[string]string{
+				"method":          "reminder.QueueDue",
+				"subscription_id": fmt.Sprint(reminder.SubscriptionID),
+				"kind":            reminder.Kind,
+			})
+		}
+
+		if len(reminders) < s.batchSize {
+			return
+		}
+	}
+}
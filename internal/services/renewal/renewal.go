@@ -0,0 +1,97 @@
+package renewal
+
+import (
+	"context"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"time"
+)
+
+// Renewer charges subscriptions that are about to run out and extends their
+// period once the payment is captured, or puts them in their grace period when
+// the charge fails. Payment retries of past due subscriptions go through here
+// too, once the dunning scheduler has planned them.
+type Renewer struct {
+	log        *jsonlog.Logger
+	subRenewer subRenewer
+	payments   paymentCollector
+	batchSize  int
+	retryAfter time.Duration
+}
+
+type subRenewer interface {
+	StartRenewal(ctx context.Context, now time.Time, retryAfter time.Duration) (*data.PaymentIntent, error)
+	CompleteRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error)
+	FailRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error)
+}
+
+type paymentCollector interface {
+	Collect(ctx context.Context, intent *data.PaymentIntent) error
+}
+
+func New(log *jsonlog.Logger, subRenewer subRenewer, payments paymentCollector, batchSize int, retryAfter time.Duration) *Renewer {
+	return &Renewer{
+		log:        log,
+		subRenewer: subRenewer,
+		payments:   payments,
+		batchSize:  batchSize,
+		retryAfter: retryAfter,
+	}
+}
+
+// RenewDue processes up to batchSize due subscriptions.
+func (r *Renewer) RenewDue(ctx context.Context) {
+	for i := 0; i < r.batchSize && ctx.Err() == nil; i++ {
+		intent, err := r.subRenewer.StartRenewal(ctx, time.Now(), r.retryAfter)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.log.PrintError(err, map[string]string{
+					"method": "renewal.RenewDue",
+				})
+			}
+			return
+		}
+		if intent == nil {
+			return
+		}
+
+		var renewal *data.Renewal
+		collectErr := r.payments.Collect(ctx, intent)
+		switch intent.Status {
+		case data.PaymentCaptured:
+			renewal, err = r.subRenewer.CompleteRenewal(ctx, intent.ID, time.Now())
+		case data.PaymentFailed:
+			renewal, err = r.subRenewer.FailRenewal(ctx, intent.ID, time.Now())
+		default:
+			// The intent could not be updated; it is picked up again once
+			// the retry delay has passed.
+			err = collectErr
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				r.log.PrintError(err, map[string]string{
+					"method":          "renewal.RenewDue",
+					"subscription_id": fmt.Sprint(intent.SubscriptionID),
+					"intent_id":       fmt.Sprint(intent.ID),
+				})
+			}
+			continue
+		}
+
+		props := map[string]string{
+			"method":          "renewal.RenewDue",
+			"subscription_id": fmt.Sprint(renewal.SubscriptionID),
+			"user_id":         fmt.Sprint(renewal.UserID),
+			"plan_id":         fmt.Sprint(renewal.PlanID),
+			"amount":          fmt.Sprint(renewal.Amount),
+			"expires_at":      renewal.ExpiresAt.Format(time.RFC3339),
+		}
+		if renewal.Status != "succeeded" {
+			props["reason"] = renewal.Reason
+			r.log.PrintInfo("subscription renewal failed", props)
+			continue
+		}
+		r.log.PrintInfo("subscription renewed", props)
+	}
+}
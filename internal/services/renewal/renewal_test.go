@@ -0,0 +1,362 @@
+package renewal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/internal/payments"
+	"sync"
+	"testing"
+	"time"
+)
+
+const graceDays = 3
+
+// memorySub is a subscription together with the columns the renewal engine
+// tracks on it.
+type memorySub struct {
+	data.Subscription
+	price        int64
+	graceUntil   time.Time
+	pastDueSince time.Time
+	attemptedAt  time.Time
+}
+
+// memoryStore keeps subscriptions, their renewal intents and dunning attempts
+// in memory, picking and settling renewals the way the database does. It
+// survives a restart of the engine by being handed to a new Renewer.
+type memoryStore struct {
+	mu       sync.Mutex
+	subs     map[int64]*memorySub
+	intents  map[int64]*data.PaymentIntent
+	attempts []*data.DunningAttempt
+	seq      int64
+}
+
+func newMemoryStore(subs ...*memorySub) *memoryStore {
+	s := &memoryStore{subs: map[int64]*memorySub{}, intents: map[int64]*data.PaymentIntent{}}
+	for _, sub := range subs {
+		s.subs[sub.ID] = sub
+	}
+	return s
+}
+
+func (s *memoryStore) due(sub *memorySub, now time.Time) bool {
+	switch sub.Status {
+	case "active", "trialing":
+		return !sub.ExpiresAt.After(now)
+	case "grace":
+		return sub.graceUntil.After(now)
+	case "past_due":
+		for _, a := range s.attempts {
+			if a.SubscriptionID == sub.ID && a.Status == data.DunningScheduled && !a.ScheduledAt.After(now) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *memoryStore) StartRenewal(ctx context.Context, now time.Time, retryAfter time.Duration) (*data.PaymentIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.subs {
+		if !s.due(sub, now) || (!sub.attemptedAt.IsZero() && sub.attemptedAt.After(now.Add(-retryAfter))) {
+			continue
+		}
+		sub.attemptedAt = now
+
+		for _, intent := range s.intents {
+			if intent.SubscriptionID == sub.ID && intent.AppliedAt.IsZero() && intent.Status != data.PaymentFailed {
+				open := *intent
+				return &open, nil
+			}
+		}
+		s.seq++
+		intent := &data.PaymentIntent{
+			ID:             s.seq,
+			SubscriptionID: sub.ID,
+			UserID:         sub.UserID,
+			PlanID:         sub.PlanID,
+			Purpose:        data.PaymentRenewal,
+			Amount:         sub.price,
+			Status:         data.PaymentCreated,
+		}
+		s.intents[intent.ID] = intent
+		created := *intent
+		return &created, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) CompleteRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[intentId]
+	if !ok || intent.Status != data.PaymentCaptured || !intent.AppliedAt.IsZero() {
+		return nil, errors.New("payment intent not found")
+	}
+	sub := s.subs[intent.SubscriptionID]
+	sub.Status = "active"
+	sub.ExpiresAt = sub.ExpiresAt.AddDate(0, 1, 0)
+	sub.graceUntil, sub.pastDueSince = time.Time{}, time.Time{}
+	s.settle(sub.ID, intentId, data.DunningSucceeded, "", now)
+	intent.AppliedAt = now
+
+	return &data.Renewal{SubscriptionID: sub.ID, UserID: sub.UserID, Amount: intent.Amount, Status: "succeeded", ExpiresAt: sub.ExpiresAt}, nil
+}
+
+func (s *memoryStore) FailRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[intentId]
+	if !ok || intent.Status != data.PaymentFailed || !intent.AppliedAt.IsZero() {
+		return nil, errors.New("payment intent not found")
+	}
+	sub := s.subs[intent.SubscriptionID]
+	switch sub.Status {
+	case "trialing":
+		sub.Status = "expired"
+	case "past_due":
+		s.settle(sub.ID, intentId, data.DunningFailed, intent.FailureReason, now)
+	default:
+		sub.Status = "grace"
+		if sub.graceUntil.IsZero() {
+			sub.graceUntil = sub.ExpiresAt.AddDate(0, 0, graceDays)
+		}
+	}
+	intent.AppliedAt = now
+
+	return &data.Renewal{SubscriptionID: sub.ID, UserID: sub.UserID, Amount: intent.Amount, Status: "failed", Reason: intent.FailureReason, ExpiresAt: sub.ExpiresAt}, nil
+}
+
+func (s *memoryStore) settle(subId, intentId int64, status, reason string, now time.Time) {
+	for _, a := range s.attempts {
+		if a.SubscriptionID == subId && a.Status == data.DunningScheduled {
+			a.Status = status
+			a.PaymentIntentID = intentId
+			a.FailureReason = reason
+			a.AttemptedAt = now
+		}
+	}
+}
+
+func (s *memoryStore) TransitionPaymentIntent(ctx context.Context, id int64, from, to, providerRef, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent := s.intents[id]
+	if !data.CanTransitionPayment(from, to) || intent.Status != from {
+		return errors.New("invalid payment intent transition")
+	}
+	intent.Status = to
+	if providerRef != "" {
+		intent.ProviderRef = providerRef
+	}
+	intent.FailureReason = reason
+	return nil
+}
+
+func (s *memoryStore) RecordPaymentRefund(ctx context.Context, id int64, amount int64) (*data.PaymentIntent, error) {
+	return nil, errors.New("not used")
+}
+
+func newTestRenewer(store *memoryStore, gateway payments.PaymentGateway) *Renewer {
+	log := jsonlog.New(io.Discard, jsonlog.LevelOff)
+	return New(log, store, payments.NewProcessor(log, gateway, store), 10, time.Minute)
+}
+
+func TestRenewDue(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		status        string
+		graceUntil    time.Time
+		attempt       *data.DunningAttempt
+		declineAbove  int64
+		wantStatus    string
+		wantExpiresAt time.Time
+		wantAttempt   string
+	}{
+		{
+			name:          "charge succeeds",
+			status:        "active",
+			wantStatus:    "active",
+			wantExpiresAt: expiresAt.AddDate(0, 1, 0),
+		},
+		{
+			name:          "charge is declined",
+			status:        "active",
+			declineAbove:  1,
+			wantStatus:    "grace",
+			wantExpiresAt: expiresAt,
+		},
+		{
+			name:          "charge in grace succeeds",
+			status:        "grace",
+			graceUntil:    expiresAt.AddDate(0, 0, graceDays),
+			wantStatus:    "active",
+			wantExpiresAt: expiresAt.AddDate(0, 1, 0),
+		},
+		{
+			name:          "trial charge is declined",
+			status:        "trialing",
+			declineAbove:  1,
+			wantStatus:    "expired",
+			wantExpiresAt: expiresAt,
+		},
+		{
+			name:          "retry succeeds",
+			status:        "past_due",
+			attempt:       &data.DunningAttempt{Attempt: 1, Status: data.DunningScheduled, ScheduledAt: now.Add(-time.Minute)},
+			wantStatus:    "active",
+			wantExpiresAt: expiresAt.AddDate(0, 1, 0),
+			wantAttempt:   data.DunningSucceeded,
+		},
+		{
+			name:          "retry is declined",
+			status:        "past_due",
+			attempt:       &data.DunningAttempt{Attempt: 1, Status: data.DunningScheduled, ScheduledAt: now.Add(-time.Minute)},
+			declineAbove:  1,
+			wantStatus:    "past_due",
+			wantExpiresAt: expiresAt,
+			wantAttempt:   data.DunningFailed,
+		},
+		{
+			name:          "retry not due yet",
+			status:        "past_due",
+			attempt:       &data.DunningAttempt{Attempt: 1, Status: data.DunningScheduled, ScheduledAt: now.Add(time.Hour)},
+			wantStatus:    "past_due",
+			wantExpiresAt: expiresAt,
+			wantAttempt:   data.DunningScheduled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &memorySub{
+				Subscription: data.Subscription{ID: 1, UserID: 7, PlanID: 2, Status: tt.status, ExpiresAt: expiresAt},
+				price:        1000,
+				graceUntil:   tt.graceUntil,
+			}
+			store := newMemoryStore(sub)
+			if tt.attempt != nil {
+				tt.attempt.SubscriptionID = sub.ID
+				store.attempts = append(store.attempts, tt.attempt)
+			}
+			gateway := payments.NewFakeGateway("secret")
+			gateway.DeclineAbove(tt.declineAbove)
+
+			newTestRenewer(store, gateway).RenewDue(context.Background())
+
+			if sub.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", sub.Status, tt.wantStatus)
+			}
+			if !sub.ExpiresAt.Equal(tt.wantExpiresAt) {
+				t.Errorf("expires at = %s, want %s", sub.ExpiresAt, tt.wantExpiresAt)
+			}
+			if tt.wantStatus == "grace" && !sub.graceUntil.Equal(expiresAt.AddDate(0, 0, graceDays)) {
+				t.Errorf("grace until = %s, want %d days after expiry", sub.graceUntil, graceDays)
+			}
+			if tt.attempt != nil && tt.attempt.Status != tt.wantAttempt {
+				t.Errorf("dunning attempt = %q, want %q", tt.attempt.Status, tt.wantAttempt)
+			}
+		})
+	}
+}
+
+func TestRenewDueChargesOnce(t *testing.T) {
+	sub := &memorySub{
+		Subscription: data.Subscription{ID: 1, UserID: 7, PlanID: 2, Status: "active", ExpiresAt: time.Now().Add(-time.Hour)},
+		price:        1000,
+	}
+	store := newMemoryStore(sub)
+	r := newTestRenewer(store, payments.NewFakeGateway("secret"))
+
+	r.RenewDue(context.Background())
+	r.RenewDue(context.Background())
+
+	if len(store.intents) != 1 {
+		t.Errorf("created %d intents, want 1", len(store.intents))
+	}
+}
+
+func TestRenewDueResumesAfterRestart(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(-time.Hour)
+
+	t.Run("scheduled retry", func(t *testing.T) {
+		// The dunning scheduler planned a retry before the engine stopped;
+		// a new engine charges it from the store once it is due.
+		sub := &memorySub{
+			Subscription: data.Subscription{ID: 1, UserID: 7, PlanID: 2, Status: "past_due", ExpiresAt: expiresAt},
+			price:        1000,
+			pastDueSince: expiresAt,
+		}
+		store := newMemoryStore(sub)
+		attempt := &data.DunningAttempt{SubscriptionID: sub.ID, Attempt: 1, Status: data.DunningScheduled, ScheduledAt: now}
+		store.attempts = append(store.attempts, attempt)
+
+		newTestRenewer(store, payments.NewFakeGateway("secret")).RenewDue(context.Background())
+
+		if sub.Status != "active" || attempt.Status != data.DunningSucceeded {
+			t.Errorf("status = %q and attempt %q, want active and %q", sub.Status, attempt.Status, data.DunningSucceeded)
+		}
+		if attempt.PaymentIntentID == 0 {
+			t.Error("attempt not linked to the payment that settled it")
+		}
+	})
+
+	t.Run("interrupted charge", func(t *testing.T) {
+		// The engine stopped after the payment was authorized; a new engine
+		// captures the same payment instead of starting another one.
+		sub := &memorySub{
+			Subscription: data.Subscription{ID: 1, UserID: 7, PlanID: 2, Status: "active", ExpiresAt: expiresAt},
+			price:        1000,
+			attemptedAt:  now.Add(-time.Hour),
+		}
+		store := newMemoryStore(sub)
+		gateway := payments.NewFakeGateway("secret")
+		intent := &data.PaymentIntent{ID: 1, SubscriptionID: sub.ID, UserID: sub.UserID, Purpose: data.PaymentRenewal, Amount: sub.price, Status: data.PaymentAuthorized}
+		ref, err := gateway.Authorize(context.Background(), *intent)
+		if err != nil {
+			t.Fatalf("Authorize() = %v", err)
+		}
+		intent.ProviderRef = ref
+		store.intents[intent.ID] = intent
+		store.seq = intent.ID
+
+		newTestRenewer(store, gateway).RenewDue(context.Background())
+
+		if len(store.intents) != 1 || intent.Status != data.PaymentCaptured || intent.AppliedAt.IsZero() {
+			t.Errorf("intents = %d, open intent %+v, want it captured and applied", len(store.intents), intent)
+		}
+		if sub.Status != "active" || !sub.ExpiresAt.Equal(expiresAt.AddDate(0, 1, 0)) {
+			t.Errorf("status = %q, expires at %s, want active until %s", sub.Status, sub.ExpiresAt, expiresAt.AddDate(0, 1, 0))
+		}
+	})
+
+	t.Run("recently tried", func(t *testing.T) {
+		// A renewal tried less than the retry delay ago is left to the run
+		// that tried it.
+		sub := &memorySub{
+			Subscription: data.Subscription{ID: 1, UserID: 7, PlanID: 2, Status: "active", ExpiresAt: expiresAt},
+			price:        1000,
+			attemptedAt:  now,
+		}
+		store := newMemoryStore(sub)
+
+		newTestRenewer(store, payments.NewFakeGateway("secret")).RenewDue(context.Background())
+
+		if len(store.intents) != 0 || sub.Status != "active" {
+			t.Errorf("intents = %d, status %q, want none and active", len(store.intents), sub.Status)
+		}
+	})
+}
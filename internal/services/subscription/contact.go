@@ -0,0 +1,44 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/validator"
+)
+
+// UpdateContact sets where the current user receives subscription
+// notifications and in which language.
+func (s *Subscription) UpdateContact(ctx context.Context, email, locale string, enabled bool) error {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	contact := &data.Contact{
+		UserID:               userId,
+		Email:                strings.TrimSpace(email),
+		Locale:               locale,
+		NotificationsEnabled: enabled,
+	}
+	if contact.Locale == "" {
+		contact.Locale = data.LocaleRU
+	}
+
+	v := validator.New()
+	if data.ValidateContact(v, contact); !v.Valid() {
+		return status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	err = s.subProvider.UpsertContact(ctx, contact)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.UpdateContact",
+		})
+		return status.Error(codes.Internal, "Internal error")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"subscriptionMService/internal/contextkeys"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/money"
+	"subscriptionMService/internal/validator"
+	"subscriptionMService/storage/postgres"
+)
+
+// CheckCoupon reports why the current user cannot redeem code on planId, or
+// nil if they can. The coupon is only redeemed by Subscribe or ChangeSubsPlan,
+// which check it again under lock.
+func (s *Subscription) CheckCoupon(ctx context.Context, code string, planId int32) error {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.subProvider.CheckCoupon(ctx, code, userId, planId)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, postgres.ErrCouponNotFound):
+		return status.Error(codes.NotFound, "Coupon not found")
+	case errors.Is(err, postgres.ErrCouponDisabled), errors.Is(err, postgres.ErrCouponNotActive):
+		return status.Error(codes.FailedPrecondition, "Coupon is not active")
+	case errors.Is(err, postgres.ErrCouponWrongPlan):
+		return status.Error(codes.InvalidArgument, "Coupon is not valid for this plan")
+	case errors.Is(err, postgres.ErrCouponExhausted), errors.Is(err, postgres.ErrCouponAlreadyUsed):
+		return status.Error(codes.ResourceExhausted, "Coupon has already been used")
+	default:
+		s.log.PrintError(err, map[string]string{
+			"method": "server.CheckCoupon",
+		})
+		return status.Error(codes.Internal, "Internal error")
+	}
+}
+
+// CreateCoupon validates and stores a new coupon. Admin only.
+func (s *Subscription) CreateCoupon(ctx context.Context, coupon *data.Coupon) error {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if coupon.DiscountType == data.DiscountFixed && coupon.Currency == "" {
+		coupon.Currency = money.Default
+	}
+	v := validator.New()
+	if data.ValidateCoupon(v, coupon); !v.Valid() {
+		return status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	err := s.subProvider.CreateCoupon(ctx, coupon)
+	if err != nil {
+		if errors.Is(err, postgres.ErrCouponCodeConflict) {
+			return status.Error(codes.AlreadyExists, "Coupon code already exists")
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.CreateCoupon",
+		})
+		return status.Error(codes.Internal, "Internal error")
+	}
+
+	s.log.PrintInfo("coupon created", map[string]string{
+		"code": coupon.Code,
+		"id":   fmt.Sprint(coupon.ID),
+	})
+	return nil
+}
+
+// DisableCoupon stops a coupon from being redeemed. Admin only.
+func (s *Subscription) DisableCoupon(ctx context.Context, code string) error {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return err
+	}
+
+	err := s.subProvider.DisableCoupon(ctx, code)
+	if err != nil {
+		if errors.Is(err, postgres.ErrCouponNotFound) {
+			return status.Error(codes.NotFound, "Coupon not found")
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.DisableCoupon",
+		})
+		return status.Error(codes.Internal, "Internal error")
+	}
+
+	s.log.PrintInfo("coupon disabled", map[string]string{
+		"code": code,
+	})
+	return nil
+}
+
+// CouponReport returns redemption totals for every coupon. Admin only.
+func (s *Subscription) CouponReport(ctx context.Context) ([]*data.CouponReport, error) {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	reports, err := s.subProvider.CouponReport(ctx)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.CouponReport",
+		})
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	return reports, nil
+}
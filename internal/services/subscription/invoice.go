@@ -0,0 +1,60 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/validator"
+	"subscriptionMService/storage/postgres"
+)
+
+// ListInvoices returns a page of the current user's invoices, newest first.
+// Lines are left out; GetInvoice returns them.
+func (s *Subscription) ListInvoices(ctx context.Context, filters data.Filters) ([]*data.Invoice, data.Metadata, error) {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, data.Metadata{}, err
+	}
+
+	v := validator.New()
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		return nil, data.Metadata{}, status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	invoices, totalRecords, err := s.subProvider.ListInvoices(ctx, userId, filters)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.ListInvoices",
+		})
+		return nil, data.Metadata{}, status.Error(codes.Internal, "Internal error")
+	}
+
+	return invoices, data.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// GetInvoice returns one of the current user's invoices by number, with its
+// lines. Other users' invoices are reported as not found.
+func (s *Subscription) GetInvoice(ctx context.Context, number string) (*data.Invoice, error) {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.subProvider.GetInvoice(ctx, number)
+	if err != nil {
+		if errors.Is(err, postgres.ErrInvoiceNotFound) {
+			return nil, status.Error(codes.NotFound, "Invoice not found")
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.GetInvoice",
+		})
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	if invoice.UserID != userId {
+		return nil, status.Error(codes.NotFound, "Invoice not found")
+	}
+	return invoice, nil
+}
@@ -0,0 +1,160 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"subscriptionMService/internal/contextkeys"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/validator"
+	"subscriptionMService/storage/postgres"
+	"time"
+)
+
+var errNothingToRefund = errors.New("nothing to refund")
+
+// RefundSubscription refunds the user's last payment as their plan's refund
+// policy allows and ends their subscription. The refund is credited against
+// the paid invoice and recorded with reasonCode and note for audit. Admin
+// only.
+func (s *Subscription) RefundSubscription(ctx context.Context, userId int64, reasonCode, note string) (*data.RefundAudit, error) {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	adminId, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &data.RefundAudit{
+		UserID:     userId,
+		ActorID:    adminId,
+		ReasonCode: reasonCode,
+		Note:       note,
+	}
+	v := validator.New()
+	if data.ValidateRefundAudit(v, audit); !v.Valid() {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrSubNotFound) {
+			return nil, status.Error(codes.NotFound, "Subscription not found")
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.RefundSubscription",
+		})
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+
+	err = s.refund(ctx, sub, audit)
+	if err != nil {
+		if errors.Is(err, errNothingToRefund) {
+			return nil, status.Error(codes.FailedPrecondition, "Nothing to refund")
+		}
+		s.log.PrintError(err, map[string]string{
+			"method":  "server.RefundSubscription",
+			"user_id": fmt.Sprint(userId),
+		})
+		return nil, status.Error(codes.Internal, "Internal error")
+	}
+	return audit, nil
+}
+
+// refundOnCancel refunds a subscription the user has just cancelled, but only
+// when its plan's refund policy ends access on cancellation. Such a refund
+// expires the subscription at once instead of at the end of its period. Under
+// RefundNone, or when the quote comes to nothing, the subscription is left
+// cancelled at period end. The cancellation stands either way, so failures
+// are only logged.
+func (s *Subscription) refundOnCancel(ctx context.Context, userId int64) {
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err == nil {
+		var plan *data.Plan
+		plan, err = s.subProvider.GetPlan(ctx, sub.PlanID)
+		if err == nil && !data.RefundEndsAccess(plan.RefundPolicy) {
+			return
+		}
+	}
+	if err == nil {
+		audit := &data.RefundAudit{
+			UserID:     userId,
+			ActorID:    userId,
+			ReasonCode: data.RefundReasonCancellation,
+		}
+		err = s.refund(ctx, sub, audit)
+	}
+	if err != nil && !errors.Is(err, errNothingToRefund) {
+		s.log.PrintError(err, map[string]string{
+			"method":  "server.Unsubscribe",
+			"user_id": fmt.Sprint(userId),
+		})
+	}
+}
+
+// refund quotes the refund of sub's last payment under its plan's policy,
+// reserves it together with audit and then pays it out. It returns
+// errNothingToRefund when the subscription has ended, was never paid for or
+// the policy gives nothing back. A refund that is reserved but fails to pay
+// out stays recorded without a payout time, so it can be settled by hand.
+func (s *Subscription) refund(ctx context.Context, sub *data.Subscription, audit *data.RefundAudit) error {
+	now := time.Now()
+	switch sub.Status {
+	case "active", "grace", "trialing", "paused":
+	case "cancelled":
+		if !sub.ExpiresAt.After(now) {
+			return errNothingToRefund
+		}
+	default:
+		return errNothingToRefund
+	}
+
+	plan, err := s.subProvider.GetPlan(ctx, sub.PlanID)
+	if err != nil {
+		return err
+	}
+	if !data.RefundEndsAccess(plan.RefundPolicy) {
+		return errNothingToRefund
+	}
+	intent, err := s.subProvider.GetLatestPaidIntent(ctx, sub.ID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPaymentIntentNotFound) {
+			return errNothingToRefund
+		}
+		return err
+	}
+
+	audit.Quote = s.refunds.Quote(*sub, *plan, *intent, now)
+	if audit.Quote.Amount == 0 {
+		return errNothingToRefund
+	}
+
+	audit.SubscriptionID = sub.ID
+	audit.PaymentIntentID = intent.ID
+	audit.Currency = intent.Currency
+	intent, err = s.subProvider.ReserveRefund(ctx, audit)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNothingToRefund) {
+			return errNothingToRefund
+		}
+		return err
+	}
+	if err := s.payments.PayOut(ctx, intent, audit.Quote.Amount); err != nil {
+		return fmt.Errorf("refund %d reserved but not paid out: %w", audit.ID, err)
+	}
+	if err := s.subProvider.MarkRefundPaidOut(ctx, audit); err != nil {
+		return err
+	}
+
+	s.log.PrintInfo("subscription refunded", map[string]string{
+		"method":          "server.refund",
+		"subscription_id": fmt.Sprint(sub.ID),
+		"user_id":         fmt.Sprint(sub.UserID),
+		"amount":          fmt.Sprint(audit.Quote.Amount),
+		"reason_code":     audit.ReasonCode,
+	})
+	return nil
+}
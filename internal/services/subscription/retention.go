@@ -0,0 +1,91 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/storage/postgres"
+	"time"
+)
+
+// PreviewUnsubscribe returns the retention offer the current user would get
+// for staying, and records it so the acceptance can be tracked. The bool is
+// false when no offer applies, including when one was already used this
+// period.
+func (s *Subscription) PreviewUnsubscribe(ctx context.Context) (data.RetentionOffer, bool, subs.Status) {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return data.RetentionOffer{}, false, subs.Status_STATUS_INVALID_USER
+	}
+
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrSubNotFound) {
+			return data.RetentionOffer{}, false, subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.PreviewUnsubscribe",
+		})
+		return data.RetentionOffer{}, false, subs.Status_STATUS_INTERNAL_ERROR
+	}
+	if sub.Status != "active" && sub.Status != "grace" {
+		return data.RetentionOffer{}, false, subs.Status_STATUS_OK
+	}
+
+	plan, err := s.getPricedPlan(ctx, sub.PlanID, sub.Currency)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.PreviewUnsubscribe",
+		})
+		return data.RetentionOffer{}, false, subs.Status_STATUS_INTERNAL_ERROR
+	}
+
+	offer, ok := s.retention.Offer(*sub, *plan, time.Now())
+	if !ok {
+		return data.RetentionOffer{}, false, subs.Status_STATUS_OK
+	}
+
+	err = s.subProvider.RecordRetentionOffer(ctx, &offer)
+	if err != nil {
+		if errors.Is(err, postgres.ErrRetentionOfferUsed) {
+			return data.RetentionOffer{}, false, subs.Status_STATUS_OK
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.PreviewUnsubscribe",
+		})
+		return data.RetentionOffer{}, false, subs.Status_STATUS_INTERNAL_ERROR
+	}
+
+	s.log.PrintInfo("retention offer made", map[string]string{
+		"userId":  fmt.Sprint(userId),
+		"offerId": fmt.Sprint(offer.ID),
+		"rule":    offer.Rule,
+		"kind":    offer.Kind,
+	})
+	return offer, true, subs.Status_STATUS_OK
+}
+
+// AcceptRetentionOffer applies an offer returned by PreviewUnsubscribe and
+// keeps the subscription running.
+func (s *Subscription) AcceptRetentionOffer(ctx context.Context, offerId int64) subs.Status {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return subs.Status_STATUS_INVALID_USER
+	}
+
+	opStatus := s.subProvider.AcceptRetentionOffer(ctx, userId, offerId)
+	if opStatus != subs.Status_STATUS_OK {
+		s.log.PrintError(s.MapStatusToError(opStatus), map[string]string{
+			"method": "server.AcceptRetentionOffer",
+		})
+		return opStatus
+	}
+
+	s.log.PrintInfo("retention offer accepted", map[string]string{
+		"userId":  fmt.Sprint(userId),
+		"offerId": fmt.Sprint(offerId),
+	})
+	return opStatus
+}
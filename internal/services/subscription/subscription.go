@@ -10,8 +10,14 @@ import (
 	"google.golang.org/grpc/status"
 	bcktgrpc "subscriptionMService/internal/clients/bucket/grpc"
 	"subscriptionMService/internal/contextkeys"
+	"subscriptionMService/internal/data"
 	"subscriptionMService/internal/jsonlog"
 	"subscriptionMService/internal/planCache"
+	"subscriptionMService/internal/proration"
+	"subscriptionMService/internal/refunds"
+	"subscriptionMService/internal/retention"
+	"subscriptionMService/internal/validator"
+	"subscriptionMService/storage/postgres"
 	"time"
 )
 
@@ -20,17 +26,52 @@ type Subscription struct {
 	subProvider   subProvider
 	planProvider  planCache.PlanProvider
 	bucketService *bcktgrpc.BucketClient
+	payments      paymentProcessor
+	proration     proration.Policy
+	retention     retention.Policy
+	refunds       refunds.Policy
 	tokenTTL      time.Duration
 }
 
 type subProvider interface {
-	Subscribe(ctx context.Context, userId int64, planId int32) (int64, subs.Status)
-	ChangeSubsPlan(ctx context.Context, userId int64, newPlanId int32) subs.Status
-	Unsubscribe(ctx context.Context, userId int64) subs.Status
+	Subscribe(ctx context.Context, userId int64, planId int32, couponCode, currency string) (int64, bool, subs.Status)
+	ChangeSubsPlan(ctx context.Context, userId int64, currentPlanId int32, change data.PlanChange, couponCode string, intentId int64) error
+	CreateProrationIntent(ctx context.Context, userId int64, currentPlanId int32, change data.PlanChange) (*data.PaymentIntent, error)
+	GetSubscription(ctx context.Context, userId int64) (*data.Subscription, error)
+	GetPlan(ctx context.Context, planId int32) (*data.Plan, error)
+	GetPlanPrice(ctx context.Context, planId int32, currency string) (int32, error)
+	CancelPendingPlanChange(ctx context.Context, userId int64) error
+	Unsubscribe(ctx context.Context, userId int64, reason string) subs.Status
 	GetSubDetails(ctx context.Context, userId int64) (int32, string, int32, time.Time)
 	CheckSubscription(ctx context.Context, userId int64) subs.Status
-	ExtractFromBalance(ctx context.Context, value int64, userId int64) (subs.Status, string, int64)
-	AddToBalance(ctx context.Context, value int64, userId int64) (subs.Status, string, int64)
+	ExtractFromBalance(ctx context.Context, value int64, userId int64, reason, reference string) (subs.Status, string, int64)
+	AddToBalance(ctx context.Context, value int64, userId int64, reason, reference string) (subs.Status, string, int64)
+	PauseSubscription(ctx context.Context, userId int64) subs.Status
+	ResumeSubscription(ctx context.Context, userId int64) (subs.Status, time.Time)
+	ListSubscriptionHistory(ctx context.Context, userId int64, filters data.Filters) ([]*data.SubEvent, int, error)
+	ListBalanceTransactions(ctx context.Context, userId int64, filters data.Filters) ([]*data.BalanceTransaction, int, error)
+	CheckCoupon(ctx context.Context, code string, userId int64, planId int32) error
+	CreateCoupon(ctx context.Context, coupon *data.Coupon) error
+	DisableCoupon(ctx context.Context, code string) error
+	CouponReport(ctx context.Context) ([]*data.CouponReport, error)
+	ActivateSubscription(ctx context.Context, subId int64) error
+	FailPendingSubscription(ctx context.Context, subId int64, reason string) error
+	UpsertContact(ctx context.Context, contact *data.Contact) error
+	RecordRetentionOffer(ctx context.Context, offer *data.RetentionOffer) error
+	AcceptRetentionOffer(ctx context.Context, userId, offerId int64) subs.Status
+	GetOpenPaymentIntent(ctx context.Context, subId int64, purpose string) (*data.PaymentIntent, error)
+	ListInvoices(ctx context.Context, userId int64, filters data.Filters) ([]*data.Invoice, int, error)
+	GetInvoice(ctx context.Context, number string) (*data.Invoice, error)
+	GetLatestPaidIntent(ctx context.Context, subId int64) (*data.PaymentIntent, error)
+	ReserveRefund(ctx context.Context, audit *data.RefundAudit) (*data.PaymentIntent, error)
+	MarkRefundPaidOut(ctx context.Context, audit *data.RefundAudit) error
+	TopUpWallet(ctx context.Context, userId int64, amount int64, currency string) (int64, error)
+}
+
+type paymentProcessor interface {
+	Collect(ctx context.Context, intent *data.PaymentIntent) error
+	Refund(ctx context.Context, intent *data.PaymentIntent, amount int64) error
+	PayOut(ctx context.Context, intent *data.PaymentIntent, amount int64) error
 }
 
 //type planProvider interface {
@@ -42,6 +83,10 @@ func New(
 	subProvider subProvider,
 	planProvider planCache.PlanProvider,
 	bucketService *bcktgrpc.BucketClient,
+	payments paymentProcessor,
+	proration proration.Policy,
+	retention retention.Policy,
+	refunds refunds.Policy,
 	tokenTTL time.Duration,
 ) *Subscription {
 	return &Subscription{
@@ -49,82 +94,476 @@ func New(
 		subProvider:   subProvider,
 		planProvider:  planProvider,
 		bucketService: bucketService,
+		payments:      payments,
+		proration:     proration,
+		retention:     retention,
+		refunds:       refunds,
 		tokenTTL:      tokenTTL,
 	}
 }
 
-func (s *Subscription) ExtractFromBalance(ctx context.Context, value int64) (subs.Status, string, int64) {
+const (
+	defaultDebitReason  = "rental"
+	defaultCreditReason = "return"
+)
+
+// ExtractFromBalance debits the user's remaining limit. reference identifies
+// the caller's object (e.g. the bucket or toy id) and is kept in the ledger.
+func (s *Subscription) ExtractFromBalance(ctx context.Context, value int64, reason, reference string) (subs.Status, string, int64) {
 	userId, err := getUserFromContext(ctx)
 	if err != nil {
 		return subs.Status_STATUS_INVALID_USER, "invalid user!", 0
 	}
+	// Paused subscriptions report STATUS_NOT_SUBSCRIBED, so rentals are
+	// blocked until the user resumes.
 	isSubscribed := s.subProvider.CheckSubscription(ctx, userId)
 	if isSubscribed == subs.Status_STATUS_NOT_SUBSCRIBED {
 		return subs.Status_STATUS_NOT_SUBSCRIBED, "user is not subscribed", 0
 	}
 
-	opStatus, msg, valueLeft := s.subProvider.ExtractFromBalance(ctx, value, userId)
+	if reason == "" {
+		reason = defaultDebitReason
+	}
+
+	opStatus, msg, valueLeft := s.subProvider.ExtractFromBalance(ctx, value, userId, reason, reference)
 	return opStatus, msg, valueLeft
 }
 
-func (s *Subscription) AddToBalance(ctx context.Context, value int64) (subs.Status, string, int64) {
+// AddToBalance credits the user's remaining limit. reference identifies the
+// caller's object (e.g. the bucket or toy id) and is kept in the ledger.
+func (s *Subscription) AddToBalance(ctx context.Context, value int64, reason, reference string) (subs.Status, string, int64) {
 	userId, err := getUserFromContext(ctx)
 	if err != nil {
 		return subs.Status_STATUS_INVALID_USER, "invalid user!", 0
 	}
+	// Paused subscriptions report STATUS_NOT_SUBSCRIBED to block rentals, but
+	// toys rented before the pause can still be returned.
 	isSubscribed := s.subProvider.CheckSubscription(ctx, userId)
-	if isSubscribed == subs.Status_STATUS_NOT_SUBSCRIBED {
+	if isSubscribed == subs.Status_STATUS_NOT_SUBSCRIBED && !s.isPaused(ctx, userId) {
 		return subs.Status_STATUS_INVALID_USER, "invalid user!", 0
 	}
-	opStatus, msg, valueLeft := s.subProvider.AddToBalance(ctx, value, userId)
+	if reason == "" {
+		reason = defaultCreditReason
+	}
+	opStatus, msg, valueLeft := s.subProvider.AddToBalance(ctx, value, userId, reason, reference)
 	return opStatus, msg, valueLeft
 
 }
 
-func (s *Subscription) Subscribe(ctx context.Context, planId int32) (int64, subs.Status) {
+// isPaused reports whether the user's subscription is paused.
+func (s *Subscription) isPaused(ctx context.Context, userId int64) bool {
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err != nil {
+		if !errors.Is(err, postgres.ErrSubNotFound) {
+			s.log.PrintError(err, map[string]string{
+				"method": "server.AddToBalance",
+			})
+		}
+		return false
+	}
+	return sub.Status == "paused"
+}
+
+// Subscribe starts or reactivates the user's subscription on planId. A
+// non-empty couponCode is redeemed as part of the same operation.
+func (s *Subscription) Subscribe(ctx context.Context, planId int32, couponCode, currency string) (int64, subs.Status) {
 	s.log.PrintInfo("Attempting to subscribe user", nil)
 	userId, err := getUserFromContext(ctx)
 	if err != nil {
 		return 0, subs.Status_STATUS_INVALID_USER
 	}
 
-	isSubscribed := s.subProvider.CheckSubscription(ctx, userId)
-	if isSubscribed == subs.Status_STATUS_SUBSCRIBED {
-		return 0, subs.Status_STATUS_ALREADY_SUBSCRIBED
+	// Cancelled and expired subscriptions are reactivated by the storage,
+	// which reports STATUS_ALREADY_SUBSCRIBED for anything still running.
+	subId, pending, subStatus := s.subProvider.Subscribe(ctx, userId, planId, couponCode, currency)
+
+	if subStatus == subs.Status_STATUS_ALREADY_SUBSCRIBED {
+		// A previous attempt may have stopped before its bucket was
+		// provisioned; retrying the subscribe picks it up again.
+		sub, err := s.subProvider.GetSubscription(ctx, userId)
+		if err == nil && sub.Status == "pending" {
+			subId, pending, subStatus = sub.ID, true, subs.Status_STATUS_OK
+		}
 	}
-
-	subId, subStatus := s.subProvider.Subscribe(ctx, userId, planId)
-
 	if subStatus != subs.Status_STATUS_OK {
 		s.log.PrintError(s.MapStatusToError(subStatus), map[string]string{
 			"method": "server.Subscribe",
 		})
 		return 0, subStatus
 	}
+	if !pending {
+		s.log.PrintInfo("subscription reactivated", map[string]string{
+			"userId": fmt.Sprint(userId),
+			"subId":  fmt.Sprint(subId),
+		})
+		return subId, subStatus
+	}
+
+	return subId, s.provisionSubscription(ctx, userId, subId)
+}
+
+// provisionSubscription collects the payment for a pending subscription,
+// creates its bucket if it has none yet and activates it. If either step
+// fails the subscription is compensated and a captured payment refunded, so
+// the user is neither charged for nothing nor left subscribed without a
+// bucket, and can simply try again. A declined payment is reported as
+// STATUS_NOT_SUBSCRIBED.
+func (s *Subscription) provisionSubscription(ctx context.Context, userId, subId int64) subs.Status {
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.subscribe",
+			"subId":  fmt.Sprint(subId),
+		})
+		return subs.Status_STATUS_INTERNAL_ERROR
+	}
+
+	intent, err := s.subProvider.GetOpenPaymentIntent(ctx, subId, data.PaymentSubscribe)
+	if err != nil && !errors.Is(err, postgres.ErrPaymentIntentNotFound) {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.subscribe",
+			"subId":  fmt.Sprint(subId),
+		})
+		return subs.Status_STATUS_INTERNAL_ERROR
+	}
+	if intent != nil {
+		if err := s.payments.Collect(ctx, intent); err != nil {
+			if intent.Status != data.PaymentFailed {
+				// The intent could not be recorded; the reconciler
+				// compensates the subscription once it goes stale.
+				s.log.PrintError(err, map[string]string{
+					"method": "server.subscribe",
+					"subId":  fmt.Sprint(subId),
+				})
+				return subs.Status_STATUS_INTERNAL_ERROR
+			}
+			s.failPending(ctx, subId, nil, "payment failed: "+intent.FailureReason)
+			return subs.Status_STATUS_NOT_SUBSCRIBED
+		}
+	}
+
+	if !sub.BucketProvisioned {
+		if status := s.createBucket(ctx, subId, intent); status != subs.Status_STATUS_OK {
+			return status
+		}
+	}
+
+	err = s.subProvider.ActivateSubscription(ctx, subId)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.subscribe",
+			"subId":  fmt.Sprint(subId),
+		})
+		return subs.Status_STATUS_INTERNAL_ERROR
+	}
+	return subs.Status_STATUS_OK
+}
+
+// failPending compensates a pending subscription, refunding intent first if
+// it was captured.
+func (s *Subscription) failPending(ctx context.Context, subId int64, intent *data.PaymentIntent, reason string) {
+	if intent != nil && intent.Status == data.PaymentCaptured {
+		if err := s.payments.Refund(ctx, intent, intent.Amount-intent.RefundedAmount); err != nil {
+			// The reconciler retries the refund once the subscription
+			// goes stale.
+			s.log.PrintError(err, map[string]string{
+				"method": "server.subscribe",
+				"subId":  fmt.Sprint(subId),
+			})
+			return
+		}
+	}
+
+	err := s.subProvider.FailPendingSubscription(ctx, subId, reason)
+	if err != nil {
+		// The reconciler compensates it once it goes stale.
+		s.log.PrintError(err, map[string]string{
+			"method": "server.subscribe",
+			"subId":  fmt.Sprint(subId),
+		})
+	}
+}
+
+// createBucket provisions the bucket of a pending subscription, compensating
+// the subscription if it cannot be created.
+func (s *Subscription) createBucket(ctx context.Context, subId int64, intent *data.PaymentIntent) subs.Status {
 	bucketResp := s.bucketService.CreateBucket(ctx)
-	if bucketResp.Status != bckt.OperationStatus_STATUS_OK {
+	if bucketResp == nil || bucketResp.Status != bckt.OperationStatus_STATUS_OK {
+		reason := "could not create bucket"
+		if bucketResp != nil && bucketResp.Msg != "" {
+			reason = bucketResp.Msg
+		}
 		s.log.PrintError(fmt.Errorf("could not create bucket for new user"), map[string]string{
 			"method": "server.subscribe",
+			"subId":  fmt.Sprint(subId),
+			"reason": reason,
+		})
+
+		s.failPending(ctx, subId, intent, reason)
+		return subs.Status_STATUS_INTERNAL_ERROR
+	}
+	return subs.Status_STATUS_OK
+}
+
+// ChangeSubsPlan moves the user to another plan using the configured proration
+// policy and returns the quoted change. Upgrades apply immediately and other
+// changes are scheduled for the end of the current period; atPeriodEnd
+// schedules upgrades too. The prorated amount of an immediate change is
+// charged before it is applied, and a declined payment is reported as
+// STATUS_NOT_SUBSCRIBED. A non-empty couponCode is redeemed against the new
+// plan.
+func (s *Subscription) ChangeSubsPlan(ctx context.Context, newPlanId int32, atPeriodEnd bool, couponCode string) (data.PlanChange, subs.Status) {
+	s.log.PrintInfo("Attempting change subscription plan", nil)
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return data.PlanChange{}, subs.Status_STATUS_INVALID_USER
+	}
+
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrSubNotFound) {
+			return data.PlanChange{}, subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.ChangeSubsPlan",
+		})
+		return data.PlanChange{}, subs.Status_STATUS_INTERNAL_ERROR
+	}
+	if sub.Status != "active" && sub.Status != "grace" {
+		return data.PlanChange{}, subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+	}
+	if sub.PlanID == newPlanId {
+		return data.PlanChange{}, subs.Status_STATUS_INVALID_PLAN
+	}
+
+	current, err := s.getPricedPlan(ctx, sub.PlanID, sub.Currency)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.ChangeSubsPlan",
+		})
+		return data.PlanChange{}, subs.Status_STATUS_INTERNAL_ERROR
+	}
+	// The new plan must be sold in the currency the subscription is paid in.
+	next, err := s.getPricedPlan(ctx, newPlanId, sub.Currency)
+	if err != nil {
+		if errors.Is(err, postgres.ErrPlanNotFound) {
+			return data.PlanChange{}, subs.Status_STATUS_INVALID_PLAN
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.ChangeSubsPlan",
+		})
+		return data.PlanChange{}, subs.Status_STATUS_INTERNAL_ERROR
+	}
+	if !next.Purchasable(time.Now()) {
+		return data.PlanChange{}, subs.Status_STATUS_INVALID_PLAN
+	}
+
+	change := s.proration.Quote(*sub, *current, *next, time.Now())
+	if atPeriodEnd && change.Immediate {
+		change = data.PlanChange{
+			PlanID:      change.PlanID,
+			Kind:        change.Kind,
+			EffectiveAt: sub.ExpiresAt,
+		}
+	}
+	s.log.PrintInfo("Plan change quoted", map[string]string{
+		"userId":      fmt.Sprint(userId),
+		"kind":        change.Kind,
+		"amount":      fmt.Sprint(change.Amount),
+		"limitTopUp":  fmt.Sprint(change.LimitTopUp),
+		"effectiveAt": change.EffectiveAt.Format(time.RFC3339),
+	})
+
+	var intent *data.PaymentIntent
+	if change.Immediate && change.Amount > 0 {
+		var chargeStatus subs.Status
+		intent, chargeStatus = s.chargePlanChange(ctx, userId, sub.PlanID, change)
+		if chargeStatus != subs.Status_STATUS_OK {
+			return data.PlanChange{}, chargeStatus
+		}
+	}
+
+	var intentId int64
+	if intent != nil {
+		intentId = intent.ID
+	}
+	err = s.subProvider.ChangeSubsPlan(ctx, userId, sub.PlanID, change, couponCode, intentId)
+	if err != nil {
+		if intent != nil {
+			s.refundPlanChange(ctx, intent)
+		}
+		switch {
+		case errors.Is(err, postgres.ErrSubNotFound):
+			return data.PlanChange{}, subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		case postgres.IsCouponError(err):
+			return data.PlanChange{}, subs.Status_STATUS_INVALID_PLAN
+		default:
+			s.log.PrintError(err, map[string]string{
+				"method": "server.ChangeSubsPlan",
+			})
+			return data.PlanChange{}, subs.Status_STATUS_INTERNAL_ERROR
+		}
+	}
+	return change, subs.Status_STATUS_OK
+}
+
+// chargePlanChange collects the prorated amount of an immediate plan change
+// from the user.
+func (s *Subscription) chargePlanChange(ctx context.Context, userId int64, currentPlanId int32, change data.PlanChange) (*data.PaymentIntent, subs.Status) {
+	intent, err := s.subProvider.CreateProrationIntent(ctx, userId, currentPlanId, change)
+	if err != nil {
+		if errors.Is(err, postgres.ErrSubNotFound) {
+			return nil, subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.ChangeSubsPlan",
+		})
+		return nil, subs.Status_STATUS_INTERNAL_ERROR
+	}
+
+	if err := s.payments.Collect(ctx, intent); err != nil {
+		if intent.Status != data.PaymentFailed {
+			s.log.PrintError(err, map[string]string{
+				"method":   "server.ChangeSubsPlan",
+				"intentId": fmt.Sprint(intent.ID),
+			})
+			return nil, subs.Status_STATUS_INTERNAL_ERROR
+		}
+		return nil, subs.Status_STATUS_NOT_SUBSCRIBED
+	}
+	return intent, subs.Status_STATUS_OK
+}
+
+// refundPlanChange gives back the prorated amount collected for a plan change
+// that could not be applied.
+func (s *Subscription) refundPlanChange(ctx context.Context, intent *data.PaymentIntent) {
+	if err := s.payments.Refund(ctx, intent, intent.Amount-intent.RefundedAmount); err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method":   "server.ChangeSubsPlan",
+			"intentId": fmt.Sprint(intent.ID),
+		})
+	}
+}
+
+// GetPendingPlanChange returns the user's scheduled plan change, if any. The
+// returned change has a zero PlanID when nothing is scheduled.
+func (s *Subscription) GetPendingPlanChange(ctx context.Context) (data.PlanChange, subs.Status) {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return data.PlanChange{}, subs.Status_STATUS_INVALID_USER
+	}
+
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrSubNotFound) {
+			return data.PlanChange{}, subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.GetPendingPlanChange",
+		})
+		return data.PlanChange{}, subs.Status_STATUS_INTERNAL_ERROR
+	}
+
+	return data.PlanChange{
+		PlanID:      sub.PendingPlanID,
+		EffectiveAt: sub.PendingChangeAt,
+	}, subs.Status_STATUS_OK
+}
+
+// getPricedPlan returns a plan with its price in currency rather than the
+// default one, so quotes are made in the currency the user pays in.
+func (s *Subscription) getPricedPlan(ctx context.Context, planId int32, currency string) (*data.Plan, error) {
+	plan, err := s.subProvider.GetPlan(ctx, planId)
+	if err != nil {
+		return nil, err
+	}
+	plan.Price, err = s.subProvider.GetPlanPrice(ctx, planId, currency)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// GetTrialEnd returns when the user's free trial ends. The returned time is
+// zero when the subscription is not in its trial.
+func (s *Subscription) GetTrialEnd(ctx context.Context) (time.Time, subs.Status) {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return time.Time{}, subs.Status_STATUS_INVALID_USER
+	}
+
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrSubNotFound) {
+			return time.Time{}, subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.GetTrialEnd",
+		})
+		return time.Time{}, subs.Status_STATUS_INTERNAL_ERROR
+	}
+
+	if sub.Status != "trialing" {
+		return time.Time{}, subs.Status_STATUS_OK
+	}
+	return sub.TrialEndsAt, subs.Status_STATUS_OK
+}
+
+// GetPlanVersion returns the version of the plan the user's subscription is
+// pinned to, which may be older than the version currently on sale.
+func (s *Subscription) GetPlanVersion(ctx context.Context) (int32, subs.Status) {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return 0, subs.Status_STATUS_INVALID_USER
+	}
+
+	sub, err := s.subProvider.GetSubscription(ctx, userId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrSubNotFound) {
+			return 0, subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.GetPlanVersion",
 		})
 		return 0, subs.Status_STATUS_INTERNAL_ERROR
 	}
 
-	return subId, subStatus
+	plan, err := s.subProvider.GetPlan(ctx, sub.PlanID)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.GetPlanVersion",
+		})
+		return 0, subs.Status_STATUS_INTERNAL_ERROR
+	}
+	return plan.Version, subs.Status_STATUS_OK
 }
 
-func (s *Subscription) ChangeSubsPlan(ctx context.Context, newPlanId int32) subs.Status {
-	s.log.PrintInfo("Attempting change subscription plan", nil)
+// CancelScheduledPlanChange drops the user's pending plan change so they stay
+// on their current plan.
+func (s *Subscription) CancelScheduledPlanChange(ctx context.Context) subs.Status {
+	s.log.PrintInfo("Attempting to cancel scheduled plan change", nil)
 	userId, err := getUserFromContext(ctx)
 	if err != nil {
 		return subs.Status_STATUS_INVALID_USER
 	}
 
-	isCompleted := s.subProvider.ChangeSubsPlan(ctx, userId, newPlanId)
-
-	return isCompleted
+	err = s.subProvider.CancelPendingPlanChange(ctx, userId)
+	if err != nil {
+		if errors.Is(err, postgres.ErrSubNotFound) {
+			return subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.CancelScheduledPlanChange",
+		})
+		return subs.Status_STATUS_INTERNAL_ERROR
+	}
+	return subs.Status_STATUS_OK
 }
 
-func (s *Subscription) Unsubscribe(ctx context.Context) subs.Status {
+// Unsubscribe cancels the caller's subscription, which keeps access until the
+// end of the paid period. On plans whose refund policy pays the unused part
+// back, the refund is made straight away and access ends with it.
+func (s *Subscription) Unsubscribe(ctx context.Context, reason string) subs.Status {
 	s.log.PrintInfo("Attempting to unsubscribe user", nil)
 	userId, err := getUserFromContext(ctx)
 	if err != nil {
@@ -136,9 +575,10 @@ func (s *Subscription) Unsubscribe(ctx context.Context) subs.Status {
 		return subs.Status_STATUS_NOT_SUBSCRIBED
 	}
 
-	// TODO: Какое то сообщение о скидке(чтобы оставить клиента)
+	// Retention offers are shown beforehand through PreviewUnsubscribe; going
+	// ahead with the cancellation declines any open offer.
 
-	isCompleted := s.subProvider.Unsubscribe(ctx, userId)
+	isCompleted := s.subProvider.Unsubscribe(ctx, userId, reason)
 
 	if isCompleted != subs.Status_STATUS_OK {
 		s.log.PrintError(s.MapStatusToError(isCompleted), map[string]string{
@@ -146,6 +586,8 @@ func (s *Subscription) Unsubscribe(ctx context.Context) subs.Status {
 		})
 		return isCompleted
 	}
+
+	s.refundOnCancel(ctx, userId)
 	return isCompleted
 
 }
@@ -156,18 +598,52 @@ func (s *Subscription) GetSubDetails(ctx context.Context) (int32, string, int32,
 	if err != nil {
 		return 0, "", 0, ""
 	}
-	isSubscribed := s.subProvider.CheckSubscription(ctx, userId)
-	if isSubscribed == subs.Status_STATUS_NOT_SUBSCRIBED {
-		return 0, "", 0, ""
-	}
 	s.log.PrintInfo("Fetching subscription details", map[string]string{
 		"userId": fmt.Sprint(userId),
 	})
 
+	// The storage only returns subscriptions the user still holds, including
+	// paused ones that CheckSubscription reports as not subscribed.
 	planId, planName, remainingLimit, expiresAt := s.subProvider.GetSubDetails(ctx, userId)
+	if planId == 0 {
+		return 0, "", 0, ""
+	}
 	return planId, planName, remainingLimit, expiresAt.Format(time.RFC3339)
 }
 
+func (s *Subscription) PauseSubscription(ctx context.Context) subs.Status {
+	s.log.PrintInfo("Attempting to pause subscription", nil)
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return subs.Status_STATUS_INVALID_USER
+	}
+
+	opStatus := s.subProvider.PauseSubscription(ctx, userId)
+	if opStatus != subs.Status_STATUS_OK {
+		s.log.PrintError(s.MapStatusToError(opStatus), map[string]string{
+			"method": "server.PauseSubscription",
+		})
+	}
+	return opStatus
+}
+
+func (s *Subscription) ResumeSubscription(ctx context.Context) (subs.Status, string) {
+	s.log.PrintInfo("Attempting to resume subscription", nil)
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return subs.Status_STATUS_INVALID_USER, ""
+	}
+
+	opStatus, expiresAt := s.subProvider.ResumeSubscription(ctx, userId)
+	if opStatus != subs.Status_STATUS_OK {
+		s.log.PrintError(s.MapStatusToError(opStatus), map[string]string{
+			"method": "server.ResumeSubscription",
+		})
+		return opStatus, ""
+	}
+	return opStatus, expiresAt.Format(time.RFC3339)
+}
+
 func (s *Subscription) CheckSubscription(ctx context.Context) subs.Status {
 	s.log.PrintInfo("Checking if user is subscribed", map[string]string{})
 
@@ -186,16 +662,74 @@ func (s *Subscription) CheckSubscription(ctx context.Context) subs.Status {
 	return isSubscribed
 }
 
-func (s *Subscription) ListPlans(ctx context.Context) []*subs.Plan {
-	s.log.PrintInfo("Listing available plans", nil)
+func (s *Subscription) ListPlans(ctx context.Context, currency string) []*subs.Plan {
+	s.log.PrintInfo("Listing available plans", map[string]string{
+		"currency": currency,
+	})
 
-	plans := s.planProvider.ListPlans(ctx)
+	plans := s.planProvider.ListPlans(ctx, currency)
 	if plans == nil {
 		s.log.PrintError(errors.New("failed to fetch plans"), nil)
 	}
 	return plans
 }
 
+// ListSubscriptionHistory returns a page of the current user's subscription
+// events, newest first.
+func (s *Subscription) ListSubscriptionHistory(ctx context.Context, filters data.Filters) ([]*data.SubEvent, data.Metadata, error) {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, data.Metadata{}, err
+	}
+
+	v := validator.New()
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		return nil, data.Metadata{}, status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	s.log.PrintInfo("Listing subscription history", map[string]string{
+		"userId": fmt.Sprint(userId),
+	})
+
+	events, totalRecords, err := s.subProvider.ListSubscriptionHistory(ctx, userId, filters)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.ListSubscriptionHistory",
+		})
+		return nil, data.Metadata{}, status.Error(codes.Internal, "Internal error")
+	}
+
+	return events, data.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// ListBalanceTransactions returns a page of the current user's ledger entries,
+// newest first.
+func (s *Subscription) ListBalanceTransactions(ctx context.Context, filters data.Filters) ([]*data.BalanceTransaction, data.Metadata, error) {
+	userId, err := getUserFromContext(ctx)
+	if err != nil {
+		return nil, data.Metadata{}, err
+	}
+
+	v := validator.New()
+	if data.ValidateFilters(v, filters); !v.Valid() {
+		return nil, data.Metadata{}, status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	s.log.PrintInfo("Listing balance transactions", map[string]string{
+		"userId": fmt.Sprint(userId),
+	})
+
+	transactions, totalRecords, err := s.subProvider.ListBalanceTransactions(ctx, userId, filters)
+	if err != nil {
+		s.log.PrintError(err, map[string]string{
+			"method": "server.ListBalanceTransactions",
+		})
+		return nil, data.Metadata{}, status.Error(codes.Internal, "Internal error")
+	}
+
+	return transactions, data.CalculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
 func (s *Subscription) MapStatusToError(code subs.Status) error {
 	switch code {
 	case subs.Status_STATUS_INVALID_PLAN:
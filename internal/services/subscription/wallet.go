@@ -0,0 +1,50 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"subscriptionMService/internal/contextkeys"
+	"subscriptionMService/internal/money"
+	"subscriptionMService/internal/validator"
+	"subscriptionMService/storage/postgres"
+)
+
+// TopUpWallet adds amount, in minor units of currency, to the wallet a user's
+// subscription is charged from and returns the new balance. The wallet is
+// opened on its first top-up. Admin only.
+func (s *Subscription) TopUpWallet(ctx context.Context, userId int64, amount int64, currency string) (int64, error) {
+	if err := contextkeys.RequireAdmin(ctx); err != nil {
+		return 0, err
+	}
+
+	if currency == "" {
+		currency = money.Default
+	}
+	v := validator.New()
+	v.Check(userId > 0, "user_id", "must be provided")
+	v.Check(amount > 0, "amount", "must be greater than zero")
+	v.Check(money.Supported(currency), "currency", "must be a supported currency code")
+	if !v.Valid() {
+		return 0, status.Error(codes.InvalidArgument, fmt.Sprint(v.Errors))
+	}
+
+	balance, err := s.subProvider.TopUpWallet(ctx, userId, amount, currency)
+	if err != nil {
+		if errors.Is(err, postgres.ErrWalletCurrency) {
+			return 0, status.Error(codes.FailedPrecondition, "Wallet is held in another currency")
+		}
+		s.log.PrintError(err, map[string]string{
+			"method": "server.TopUpWallet",
+		})
+		return 0, status.Error(codes.Internal, "Internal error")
+	}
+
+	s.log.PrintInfo("wallet topped up", map[string]string{
+		"userId": fmt.Sprint(userId),
+		"amount": money.Format(amount, currency),
+	})
+	return balance, nil
+}
@@ -0,0 +1,280 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/internal/payments"
+	"subscriptionMService/storage/postgres"
+	"time"
+)
+
+// maxPayloadBytes caps the size of a webhook request body.
+const maxPayloadBytes = 1 << 20
+
+// Receiver accepts payment gateway webhooks and applies them to payment
+// intents and the subscriptions they pay for. Every verified event is stored
+// under its event id before it is applied, so a redelivered event is applied
+// once and a stored one can be replayed after an incident.
+type Receiver struct {
+	log      *jsonlog.Logger
+	verifier webhookVerifier
+	store    webhookStore
+}
+
+type webhookVerifier interface {
+	VerifyWebhook(payload []byte, header http.Header) (*payments.WebhookEvent, error)
+}
+
+type webhookStore interface {
+	RecordWebhook(ctx context.Context, delivery *data.WebhookDelivery) (bool, error)
+	FinishWebhook(ctx context.Context, id string, processErr string) error
+	GetPaymentIntentByRef(ctx context.Context, providerRef string) (*data.PaymentIntent, error)
+	TransitionPaymentIntent(ctx context.Context, id int64, from, to, providerRef, reason string) error
+	SyncPaymentRefund(ctx context.Context, id int64, total int64) (*data.PaymentIntent, error)
+	DisputePaymentIntent(ctx context.Context, id int64, reason string) error
+	GetSubscription(ctx context.Context, userId int64) (*data.Subscription, error)
+	ActivateSubscription(ctx context.Context, subId int64) error
+	FailPendingSubscription(ctx context.Context, subId int64, reason string) error
+	CompleteRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error)
+	FailRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error)
+}
+
+// New returns a Receiver. verifier may be nil when the Receiver is only used
+// to replay stored webhooks.
+func New(log *jsonlog.Logger, verifier webhookVerifier, store webhookStore) *Receiver {
+	return &Receiver{
+		log:      log,
+		verifier: verifier,
+		store:    store,
+	}
+}
+
+// ServeHTTP handles one webhook delivery. Requests that fail verification get
+// 401. Events that could not be applied get 500 so that the gateway delivers
+// them again; events already applied are acknowledged without being applied
+// again.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	event, err := r.verifier.VerifyWebhook(payload, req.Header)
+	if err != nil {
+		r.log.PrintInfo("webhook rejected", map[string]string{
+			"method": "webhook.ServeHTTP",
+			"reason": err.Error(),
+		})
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	if event.ID == "" {
+		http.Error(w, "missing event id", http.StatusBadRequest)
+		return
+	}
+
+	delivery := &data.WebhookDelivery{
+		ID:          event.ID,
+		Type:        event.Type,
+		ProviderRef: event.ProviderRef,
+		Payload:     payload,
+	}
+	fresh, err := r.store.RecordWebhook(req.Context(), delivery)
+	if err != nil {
+		r.log.PrintError(err, map[string]string{
+			"method":   "webhook.ServeHTTP",
+			"event_id": event.ID,
+		})
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if !fresh {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := r.process(req.Context(), *event); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Replay applies a stored webhook again, whether or not it was processed
+// before. The signature was checked when the webhook was received, so only
+// the payload is decoded here. Applying an event is idempotent: transitions
+// that already happened are skipped.
+func (r *Receiver) Replay(ctx context.Context, delivery *data.WebhookDelivery) error {
+	var event payments.WebhookEvent
+	if err := json.Unmarshal(delivery.Payload, &event); err != nil {
+		return err
+	}
+	event.ID = delivery.ID
+	return r.process(ctx, event)
+}
+
+// process applies event and records the outcome against the stored webhook.
+func (r *Receiver) process(ctx context.Context, event payments.WebhookEvent) error {
+	applyErr := r.apply(ctx, event)
+
+	processErr := ""
+	if applyErr != nil {
+		processErr = applyErr.Error()
+		r.log.PrintError(applyErr, map[string]string{
+			"method":       "webhook.process",
+			"event_id":     event.ID,
+			"type":         event.Type,
+			"provider_ref": event.ProviderRef,
+		})
+	}
+	if err := r.store.FinishWebhook(ctx, event.ID, processErr); err != nil {
+		return err
+	}
+	if applyErr != nil {
+		return applyErr
+	}
+
+	r.log.PrintInfo("webhook processed", map[string]string{
+		"method":       "webhook.process",
+		"event_id":     event.ID,
+		"type":         event.Type,
+		"provider_ref": event.ProviderRef,
+	})
+	return nil
+}
+
+// apply drives the payment intent event refers to, and the subscription it
+// pays for, to the state the gateway reports. A full refund of the payment for
+// the current period ends the subscription's access, and a dispute restricts
+// the subscription until it expires; both are recorded in its history.
+func (r *Receiver) apply(ctx context.Context, event payments.WebhookEvent) error {
+	switch event.Type {
+	case payments.WebhookSucceeded, payments.WebhookFailed, payments.WebhookRefunded, payments.WebhookDisputed:
+	default:
+		// Other events carry nothing that is not already recorded.
+		return nil
+	}
+
+	intent, err := r.store.GetPaymentIntentByRef(ctx, event.ProviderRef)
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case payments.WebhookSucceeded:
+		return r.applySucceeded(ctx, intent)
+	case payments.WebhookFailed:
+		return r.applyFailed(ctx, intent, event.Reason)
+	case payments.WebhookRefunded:
+		refunded, err := r.store.SyncPaymentRefund(ctx, intent.ID, event.Amount)
+		if err != nil {
+			return err
+		}
+		if refunded.Status == data.PaymentRefunded && intent.Status != data.PaymentRefunded {
+			r.log.PrintInfo("payment refunded in full at the gateway", map[string]string{
+				"method":          "webhook.apply",
+				"intent_id":       fmt.Sprint(intent.ID),
+				"subscription_id": fmt.Sprint(intent.SubscriptionID),
+			})
+		}
+		return nil
+	default:
+		if intent.Status == data.PaymentDisputed {
+			return nil
+		}
+		reason := event.Reason
+		if reason == "" {
+			reason = "payment disputed"
+		}
+		return r.store.DisputePaymentIntent(ctx, intent.ID, reason)
+	}
+}
+
+// applySucceeded captures the intent and finishes what it paid for. A pending
+// subscription whose bucket has not been provisioned yet is left to the
+// subscribe call or the reconciler, since provisioning needs the user's token.
+func (r *Receiver) applySucceeded(ctx context.Context, intent *data.PaymentIntent) error {
+	switch intent.Status {
+	case data.PaymentAuthorized:
+		err := r.store.TransitionPaymentIntent(ctx, intent.ID, intent.Status, data.PaymentCaptured, "", "")
+		if err != nil {
+			return err
+		}
+	case data.PaymentCaptured, data.PaymentRefunded, data.PaymentDisputed:
+	default:
+		return fmt.Errorf("payment intent %d succeeded at the gateway but is %s", intent.ID, intent.Status)
+	}
+	if !intent.AppliedAt.IsZero() {
+		return nil
+	}
+
+	switch intent.Purpose {
+	case data.PaymentSubscribe:
+		sub, err := r.store.GetSubscription(ctx, intent.UserID)
+		if err != nil {
+			return err
+		}
+		if sub.ID != intent.SubscriptionID || sub.Status != "pending" || !sub.BucketProvisioned {
+			return nil
+		}
+		return ignoreApplied(r.store.ActivateSubscription(ctx, intent.SubscriptionID))
+	case data.PaymentProration:
+		// Plan changes are applied by the call that collected them.
+		return nil
+	default:
+		_, err := r.store.CompleteRenewal(ctx, intent.ID, time.Now())
+		return ignoreApplied(err)
+	}
+}
+
+// applyFailed fails the intent and what it was paying for.
+func (r *Receiver) applyFailed(ctx context.Context, intent *data.PaymentIntent, reason string) error {
+	if reason == "" {
+		reason = "payment failed"
+	}
+	switch intent.Status {
+	case data.PaymentCreated, data.PaymentAuthorized:
+		err := r.store.TransitionPaymentIntent(ctx, intent.ID, intent.Status, data.PaymentFailed, "", reason)
+		if err != nil {
+			return err
+		}
+	case data.PaymentFailed, data.PaymentCancelled:
+	default:
+		return fmt.Errorf("payment intent %d failed at the gateway but is %s", intent.ID, intent.Status)
+	}
+	if !intent.AppliedAt.IsZero() {
+		return nil
+	}
+
+	switch intent.Purpose {
+	case data.PaymentSubscribe:
+		return ignoreApplied(r.store.FailPendingSubscription(ctx, intent.SubscriptionID, reason))
+	case data.PaymentProration:
+		return nil
+	default:
+		_, err := r.store.FailRenewal(ctx, intent.ID, time.Now())
+		return ignoreApplied(err)
+	}
+}
+
+// ignoreApplied drops the not found errors storage returns when the outcome
+// of a payment has already been applied by someone else.
+func ignoreApplied(err error) error {
+	if errors.Is(err, postgres.ErrSubNotFound) || errors.Is(err, postgres.ErrPaymentIntentNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,290 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/jsonlog"
+	"subscriptionMService/internal/payments"
+	"subscriptionMService/storage/postgres"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore keeps webhooks and payment intents in memory, recording every
+// delivery the way the database does and counting the renewals applied.
+type memoryStore struct {
+	mu          sync.Mutex
+	deliveries  map[string]*data.WebhookDelivery
+	intents     map[string]*data.PaymentIntent
+	renewals    int
+	transitions int
+	failRenewal error
+}
+
+func newMemoryStore(intents ...data.PaymentIntent) *memoryStore {
+	s := &memoryStore{deliveries: map[string]*data.WebhookDelivery{}, intents: map[string]*data.PaymentIntent{}}
+	for i := range intents {
+		s.intents[intents[i].ProviderRef] = &intents[i]
+	}
+	return s
+}
+
+func (s *memoryStore) RecordWebhook(ctx context.Context, delivery *data.WebhookDelivery) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.deliveries[delivery.ID]
+	if !ok {
+		stored = &data.WebhookDelivery{ID: delivery.ID, Type: delivery.Type, ProviderRef: delivery.ProviderRef, Payload: delivery.Payload}
+		s.deliveries[delivery.ID] = stored
+	}
+	if !stored.ProcessedAt.IsZero() {
+		return false, nil
+	}
+	stored.Attempts++
+	delivery.Attempts = stored.Attempts
+	return true, nil
+}
+
+func (s *memoryStore) FinishWebhook(ctx context.Context, id string, processErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.deliveries[id]
+	if !ok {
+		return postgres.ErrWebhookNotFound
+	}
+	stored.LastError = processErr
+	if processErr == "" {
+		stored.ProcessedAt = time.Now()
+	}
+	return nil
+}
+
+func (s *memoryStore) GetPaymentIntentByRef(ctx context.Context, providerRef string) (*data.PaymentIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[providerRef]
+	if !ok {
+		return nil, postgres.ErrPaymentIntentNotFound
+	}
+	found := *intent
+	return &found, nil
+}
+
+func (s *memoryStore) TransitionPaymentIntent(ctx context.Context, id int64, from, to, providerRef, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, intent := range s.intents {
+		if intent.ID == id && intent.Status == from && data.CanTransitionPayment(from, to) {
+			intent.Status = to
+			intent.FailureReason = reason
+			s.transitions++
+			return nil
+		}
+	}
+	return postgres.ErrInvalidPaymentTransition
+}
+
+func (s *memoryStore) SyncPaymentRefund(ctx context.Context, id int64, total int64) (*data.PaymentIntent, error) {
+	return nil, errors.New("not used")
+}
+
+func (s *memoryStore) DisputePaymentIntent(ctx context.Context, id int64, reason string) error {
+	return errors.New("not used")
+}
+
+func (s *memoryStore) GetSubscription(ctx context.Context, userId int64) (*data.Subscription, error) {
+	return nil, postgres.ErrSubNotFound
+}
+
+func (s *memoryStore) ActivateSubscription(ctx context.Context, subId int64) error {
+	return errors.New("not used")
+}
+
+func (s *memoryStore) FailPendingSubscription(ctx context.Context, subId int64, reason string) error {
+	return errors.New("not used")
+}
+
+func (s *memoryStore) CompleteRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.failRenewal; err != nil {
+		s.failRenewal = nil
+		return nil, err
+	}
+	for _, intent := range s.intents {
+		if intent.ID == intentId && intent.Status == data.PaymentCaptured && intent.AppliedAt.IsZero() {
+			intent.AppliedAt = now
+			s.renewals++
+			return &data.Renewal{SubscriptionID: intent.SubscriptionID, Status: "succeeded"}, nil
+		}
+	}
+	return nil, postgres.ErrPaymentIntentNotFound
+}
+
+func (s *memoryStore) FailRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error) {
+	return nil, errors.New("not used")
+}
+
+func renewalIntent() data.PaymentIntent {
+	return data.PaymentIntent{
+		ID:             1,
+		SubscriptionID: 10,
+		UserID:         7,
+		Purpose:        data.PaymentRenewal,
+		Amount:         1000,
+		Status:         data.PaymentAuthorized,
+		ProviderRef:    "fake_1_1",
+	}
+}
+
+func newTestReceiver(store *memoryStore) *Receiver {
+	return New(jsonlog.New(io.Discard, jsonlog.LevelOff), payments.NewFakeGateway("secret"), store)
+}
+
+// deliver posts payload to r signed with secret at t, and returns the status
+// code of the response.
+func deliver(r *Receiver, payload []byte, secret string, t time.Time) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payments", strings.NewReader(string(payload)))
+	if secret != "" {
+		req.Header.Set(payments.SignatureHeader, payments.Sign(secret, payload, t))
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func succeededPayload(t *testing.T, id string) []byte {
+	t.Helper()
+	payload, _, err := payments.NewFakeGateway("secret").SignWebhook(payments.WebhookEvent{
+		ID:          id,
+		Type:        payments.WebhookSucceeded,
+		ProviderRef: "fake_1_1",
+		Amount:      1000,
+	})
+	if err != nil {
+		t.Fatalf("SignWebhook() = %v", err)
+	}
+	return payload
+}
+
+func TestServeHTTPVerifiesSignature(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		secret       string
+		signedAt     time.Time
+		wantCode     int
+		wantRenewals int
+	}{
+		{name: "valid", secret: "secret", signedAt: now, wantCode: http.StatusOK, wantRenewals: 1},
+		{name: "within tolerance", secret: "secret", signedAt: now.Add(-payments.SignatureTolerance + time.Minute), wantCode: http.StatusOK, wantRenewals: 1},
+		{name: "too old", secret: "secret", signedAt: now.Add(-payments.SignatureTolerance - time.Minute), wantCode: http.StatusUnauthorized},
+		{name: "too far ahead", secret: "secret", signedAt: now.Add(payments.SignatureTolerance + time.Minute), wantCode: http.StatusUnauthorized},
+		{name: "wrong secret", secret: "other secret", signedAt: now, wantCode: http.StatusUnauthorized},
+		{name: "unsigned", wantCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore(renewalIntent())
+			r := newTestReceiver(store)
+
+			if code := deliver(r, succeededPayload(t, "evt_1"), tt.secret, tt.signedAt); code != tt.wantCode {
+				t.Errorf("status = %d, want %d", code, tt.wantCode)
+			}
+			if store.renewals != tt.wantRenewals {
+				t.Errorf("renewals applied = %d, want %d", store.renewals, tt.wantRenewals)
+			}
+			if tt.wantCode == http.StatusUnauthorized && len(store.deliveries) != 0 {
+				t.Errorf("stored %d deliveries of a rejected webhook", len(store.deliveries))
+			}
+		})
+	}
+}
+
+func TestServeHTTPAppliesDuplicateOnce(t *testing.T) {
+	store := newMemoryStore(renewalIntent())
+	r := newTestReceiver(store)
+	payload := succeededPayload(t, "evt_1")
+
+	for i := 0; i < 2; i++ {
+		if code := deliver(r, payload, "secret", time.Now()); code != http.StatusOK {
+			t.Fatalf("delivery %d: status = %d, want %d", i+1, code, http.StatusOK)
+		}
+	}
+	if store.transitions != 1 || store.renewals != 1 {
+		t.Errorf("applied %d transitions and %d renewals, want 1 and 1", store.transitions, store.renewals)
+	}
+	if got := store.deliveries["evt_1"].Attempts; got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestFailedDeliveryIsRetriedAndReplayed(t *testing.T) {
+	store := newMemoryStore(renewalIntent())
+	store.failRenewal = errors.New("database unavailable")
+	r := newTestReceiver(store)
+	payload := succeededPayload(t, "evt_1")
+
+	if code := deliver(r, payload, "secret", time.Now()); code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", code, http.StatusInternalServerError)
+	}
+	stored := store.deliveries["evt_1"]
+	if stored.LastError == "" || !stored.ProcessedAt.IsZero() {
+		t.Fatalf("failed delivery stored as %+v, want an error and no processed time", stored)
+	}
+	if store.renewals != 0 {
+		t.Fatalf("renewals applied = %d, want 0", store.renewals)
+	}
+
+	// The gateway delivers the event again; the intent is already captured,
+	// so only the renewal is left to apply.
+	if code := deliver(r, payload, "secret", time.Now()); code != http.StatusOK {
+		t.Fatalf("redelivery status = %d, want %d", code, http.StatusOK)
+	}
+	if store.renewals != 1 || stored.Attempts != 2 || stored.ProcessedAt.IsZero() {
+		t.Errorf("after redelivery: %d renewals, delivery %+v", store.renewals, stored)
+	}
+
+	// Replaying a processed event applies nothing twice.
+	if err := r.Replay(context.Background(), stored); err != nil {
+		t.Fatalf("Replay() = %v", err)
+	}
+	if store.renewals != 1 || store.transitions != 1 {
+		t.Errorf("after replay: %d renewals and %d transitions, want 1 and 1", store.renewals, store.transitions)
+	}
+}
+
+func TestReplayAppliesStoredDelivery(t *testing.T) {
+	store := newMemoryStore(renewalIntent())
+	store.failRenewal = errors.New("database unavailable")
+	r := newTestReceiver(store)
+
+	if code := deliver(r, succeededPayload(t, "evt_1"), "secret", time.Now()); code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", code, http.StatusInternalServerError)
+	}
+
+	// Replay is not verified again, so it works without a gateway.
+	replayer := New(jsonlog.New(io.Discard, jsonlog.LevelOff), nil, store)
+	if err := replayer.Replay(context.Background(), store.deliveries["evt_1"]); err != nil {
+		t.Fatalf("Replay() = %v", err)
+	}
+	if store.renewals != 1 {
+		t.Errorf("renewals applied = %d, want 1", store.renewals)
+	}
+	if stored := store.deliveries["evt_1"]; stored.LastError != "" || stored.ProcessedAt.IsZero() {
+		t.Errorf("replayed delivery stored as %+v, want it processed", stored)
+	}
+}
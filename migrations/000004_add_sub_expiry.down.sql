@@ -0,0 +1 @@
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_expires_at_check CHECK (expires_at > NOW()) NOT VALID;
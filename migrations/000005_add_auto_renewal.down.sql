@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS subscription_renewals;
+DROP TABLE IF EXISTS wallets;
+
+UPDATE subscriptions SET status = 'active' WHERE status = 'grace';
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused'));
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS renewal_attempted_at,
+    DROP COLUMN IF EXISTS grace_until;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS grace_period_days,
+    DROP COLUMN IF EXISTS renewal_lead_hours,
+    DROP COLUMN IF EXISTS auto_renew;
@@ -0,0 +1,32 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS auto_renew BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS renewal_lead_hours INT NOT NULL DEFAULT 24 CHECK (renewal_lead_hours >= 0),
+    ADD COLUMN IF NOT EXISTS grace_period_days INT NOT NULL DEFAULT 3 CHECK (grace_period_days >= 0);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS grace_until TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS renewal_attempted_at TIMESTAMP;
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused', 'grace'));
+
+CREATE TABLE IF NOT EXISTS wallets (
+    user_id BIGINT PRIMARY KEY CHECK (user_id > 0),
+    balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS subscription_renewals (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    plan_id INT NOT NULL,
+    amount INT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('succeeded', 'failed')),
+    reason VARCHAR(200) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_renewals_subscription_id ON subscription_renewals(subscription_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_subscriptions_user_id_unique;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS cancel_reason,
+    DROP COLUMN IF EXISTS cancelled_at;
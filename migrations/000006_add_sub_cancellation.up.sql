@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS cancel_reason VARCHAR(500);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_user_id_unique ON subscriptions(user_id);
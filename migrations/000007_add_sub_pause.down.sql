@@ -0,0 +1,7 @@
+UPDATE subscriptions SET status = 'active', paused_at = NULL WHERE status = 'paused';
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS paused_at;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS max_pause_days;
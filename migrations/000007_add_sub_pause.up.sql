@@ -0,0 +1,5 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS max_pause_days INT NOT NULL DEFAULT 30 CHECK (max_pause_days >= 0);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS subscription_events (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    event_type VARCHAR(30) NOT NULL,
+    plan_id INT,
+    status VARCHAR(20) NOT NULL,
+    remaining_limit INT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    amount BIGINT NOT NULL DEFAULT 0,
+    details VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_events_user_id_created_at ON subscription_events(user_id, created_at DESC);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS balance_transactions;
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_remaining_limit_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_remaining_limit_check CHECK (remaining_limit > 0) NOT VALID;
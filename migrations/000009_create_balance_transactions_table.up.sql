@@ -0,0 +1,23 @@
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_remaining_limit_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_remaining_limit_check CHECK (remaining_limit >= 0);
+
+CREATE TABLE IF NOT EXISTS balance_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    entry_type VARCHAR(10) NOT NULL CHECK (entry_type IN ('debit', 'credit')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    counter_account VARCHAR(30) NOT NULL,
+    reason VARCHAR(100) NOT NULL,
+    reference VARCHAR(200) NOT NULL DEFAULT '',
+    balance_after BIGINT NOT NULL CHECK (balance_after >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_transactions_user_id_created_at ON balance_transactions(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_balance_transactions_subscription_id ON balance_transactions(subscription_id);
+
+INSERT INTO balance_transactions (subscription_id, user_id, entry_type, amount, counter_account, reason, balance_after)
+SELECT id, user_id, 'credit', remaining_limit, 'opening_balance', 'opening balance', remaining_limit
+FROM subscriptions
+WHERE remaining_limit > 0;
@@ -0,0 +1,7 @@
+ALTER TABLE subscription_renewals ALTER COLUMN amount TYPE INT;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS outstanding_amount,
+    DROP COLUMN IF EXISTS pending_change_at,
+    DROP COLUMN IF EXISTS pending_plan_id,
+    DROP COLUMN IF EXISTS period_started_at;
@@ -0,0 +1,18 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS period_started_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS pending_plan_id INT REFERENCES subscription_plans(id),
+    ADD COLUMN IF NOT EXISTS pending_change_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS outstanding_amount BIGINT NOT NULL DEFAULT 0 CHECK (outstanding_amount >= 0);
+
+UPDATE subscriptions s
+SET period_started_at = s.expires_at - p.duration_months * INTERVAL '1 month'
+FROM subscription_plans p
+WHERE p.id = s.plan_id AND s.period_started_at IS NULL;
+
+UPDATE subscriptions SET period_started_at = NOW() WHERE period_started_at IS NULL;
+
+ALTER TABLE subscriptions
+    ALTER COLUMN period_started_at SET DEFAULT NOW(),
+    ALTER COLUMN period_started_at SET NOT NULL;
+
+ALTER TABLE subscription_renewals ALTER COLUMN amount TYPE BIGINT;
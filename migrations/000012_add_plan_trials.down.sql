@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS trial_redemptions;
+
+UPDATE subscriptions SET status = 'expired' WHERE status = 'trialing';
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused', 'grace'));
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS trial_ends_at;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS trial_rental_limit,
+    DROP COLUMN IF EXISTS trial_days;
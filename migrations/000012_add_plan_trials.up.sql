@@ -0,0 +1,16 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS trial_days INT NOT NULL DEFAULT 0 CHECK (trial_days >= 0),
+    ADD COLUMN IF NOT EXISTS trial_rental_limit INT NOT NULL DEFAULT 0 CHECK (trial_rental_limit >= 0);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS trial_ends_at TIMESTAMP;
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused', 'grace', 'trialing'));
+
+CREATE TABLE IF NOT EXISTS trial_redemptions (
+    user_id BIGINT PRIMARY KEY CHECK (user_id > 0),
+    plan_id INT NOT NULL REFERENCES subscription_plans(id),
+    started_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
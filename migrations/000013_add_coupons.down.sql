@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS discount_amount;
+
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS coupons (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    discount_type TEXT NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value BIGINT NOT NULL DEFAULT 0 CHECK (discount_value >= 0),
+    bonus_limit INT NOT NULL DEFAULT 0 CHECK (bonus_limit >= 0),
+    max_redemptions INT NOT NULL DEFAULT 0 CHECK (max_redemptions >= 0),
+    per_user_limit INT NOT NULL DEFAULT 1 CHECK (per_user_limit > 0),
+    valid_from TIMESTAMP,
+    valid_until TIMESTAMP,
+    plan_ids INT[] NOT NULL DEFAULT '{}',
+    disabled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (discount_type <> 'percent' OR discount_value <= 100),
+    CHECK (valid_until IS NULL OR valid_from IS NULL OR valid_until > valid_from)
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    coupon_id BIGINT NOT NULL REFERENCES coupons(id),
+    user_id BIGINT NOT NULL,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id),
+    plan_id INT NOT NULL REFERENCES subscription_plans(id),
+    discount_amount BIGINT NOT NULL DEFAULT 0,
+    bonus_limit INT NOT NULL DEFAULT 0,
+    redeemed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_user ON coupon_redemptions(coupon_id, user_id);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS discount_amount BIGINT NOT NULL DEFAULT 0 CHECK (discount_amount >= 0);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS retention_offers;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,31 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+
+UPDATE subscriptions s
+SET created_at = COALESCE(
+    (SELECT MIN(e.created_at) FROM subscription_events e WHERE e.subscription_id = s.id),
+    s.period_started_at)
+WHERE s.created_at IS NULL;
+
+ALTER TABLE subscriptions
+    ALTER COLUMN created_at SET DEFAULT NOW(),
+    ALTER COLUMN created_at SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS retention_offers (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    plan_id INT NOT NULL REFERENCES subscription_plans(id),
+    rule VARCHAR(50) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('discount', 'free_month', 'bonus_limit')),
+    discount_amount BIGINT NOT NULL DEFAULT 0 CHECK (discount_amount >= 0),
+    bonus_limit INT NOT NULL DEFAULT 0 CHECK (bonus_limit >= 0),
+    free_months INT NOT NULL DEFAULT 0 CHECK (free_months >= 0),
+    period_started_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'offered' CHECK (status IN ('offered', 'accepted', 'declined')),
+    offered_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP,
+    UNIQUE (subscription_id, period_started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_offers_user_id ON retention_offers(user_id);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL UNIQUE REFERENCES subscription_events(id) ON DELETE CASCADE,
+    subscription_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    event_type VARCHAR(30) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    published_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unpublished ON outbox_events(next_attempt_at) WHERE published_at IS NULL;
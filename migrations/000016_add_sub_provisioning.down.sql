@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_subscriptions_pending;
+
+UPDATE subscriptions SET status = 'expired' WHERE status IN ('pending', 'failed');
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused', 'grace', 'trialing'));
@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused', 'grace', 'trialing', 'pending', 'failed'));
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_pending ON subscriptions(period_started_at) WHERE status = 'pending';
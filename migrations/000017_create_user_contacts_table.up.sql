@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_contacts (
+    user_id BIGINT PRIMARY KEY CHECK (user_id > 0),
+    email VARCHAR(254) NOT NULL,
+    locale VARCHAR(5) NOT NULL DEFAULT 'ru' CHECK (locale IN ('ru', 'en')),
+    notifications_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
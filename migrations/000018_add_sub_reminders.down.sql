@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS reminders_sent;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS expiry_reminder_days,
+    DROP COLUMN IF EXISTS low_limit_threshold;
@@ -0,0 +1,11 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS low_limit_threshold INT NOT NULL DEFAULT 1 CHECK (low_limit_threshold >= 0),
+    ADD COLUMN IF NOT EXISTS expiry_reminder_days INT NOT NULL DEFAULT 3 CHECK (expiry_reminder_days >= 0);
+
+CREATE TABLE IF NOT EXISTS reminders_sent (
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    kind VARCHAR(30) NOT NULL,
+    period_started_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, kind, period_started_at)
+);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_subscription_plans_family_version;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS family_id;
@@ -0,0 +1,10 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS family_id INT REFERENCES subscription_plans(id),
+    ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1 CHECK (version > 0),
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+UPDATE subscription_plans SET family_id = id WHERE family_id IS NULL;
+
+ALTER TABLE subscription_plans ALTER COLUMN family_id SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_plans_family_version ON subscription_plans(family_id, version);
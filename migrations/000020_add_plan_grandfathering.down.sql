@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_subscription_plans_family_current;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS grandfathered;
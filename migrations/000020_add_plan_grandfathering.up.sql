@@ -0,0 +1,4 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS grandfathered BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE INDEX IF NOT EXISTS idx_subscription_plans_family_current ON subscription_plans(family_id, version) WHERE archived_at IS NULL;
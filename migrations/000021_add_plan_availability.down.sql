@@ -0,0 +1,8 @@
+ALTER TABLE subscription_plans
+    DROP CONSTRAINT IF EXISTS subscription_plans_availability_check;
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS sort_order,
+    DROP COLUMN IF EXISTS available_until,
+    DROP COLUMN IF EXISTS available_from,
+    DROP COLUMN IF EXISTS visibility;
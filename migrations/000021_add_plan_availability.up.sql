@@ -0,0 +1,9 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS visibility VARCHAR(10) NOT NULL DEFAULT 'public' CHECK (visibility IN ('public', 'hidden')),
+    ADD COLUMN IF NOT EXISTS available_from TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS available_until TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS sort_order INT NOT NULL DEFAULT 0;
+
+ALTER TABLE subscription_plans
+    ADD CONSTRAINT subscription_plans_availability_check
+    CHECK (available_from IS NULL OR available_until IS NULL OR available_from < available_until);
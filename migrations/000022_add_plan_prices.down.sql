@@ -0,0 +1,10 @@
+ALTER TABLE coupons
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE wallets
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS currency;
+
+DROP TABLE IF EXISTS plan_prices;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS plan_prices (
+    plan_id INT NOT NULL REFERENCES subscription_plans(id),
+    currency CHAR(3) NOT NULL,
+    amount_minor INT NOT NULL CHECK (amount_minor > 0),
+    PRIMARY KEY (plan_id, currency)
+);
+
+-- Existing prices become the KZT price of each plan.
+INSERT INTO plan_prices (plan_id, currency, amount_minor)
+SELECT id, 'KZT', price FROM subscription_plans
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'KZT';
+
+ALTER TABLE wallets
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'KZT';
+
+ALTER TABLE coupons
+    ADD COLUMN IF NOT EXISTS currency CHAR(3);
+
+UPDATE coupons SET currency = 'KZT' WHERE discount_type = 'fixed' AND currency IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS bucket_provisioned;
+
+DROP TABLE IF EXISTS payment_intents;
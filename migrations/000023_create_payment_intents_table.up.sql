@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS payment_intents (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id),
+    user_id BIGINT NOT NULL,
+    plan_id INT NOT NULL REFERENCES subscription_plans(id),
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('subscribe', 'renewal')),
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    discount_applied BIGINT NOT NULL DEFAULT 0 CHECK (discount_applied >= 0),
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'created'
+        CHECK (status IN ('created', 'authorized', 'captured', 'failed', 'cancelled', 'refunded')),
+    provider_ref TEXT,
+    failure_reason TEXT NOT NULL DEFAULT '',
+    refunded_amount BIGINT NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0 AND refunded_amount <= amount),
+    applied_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_intents_subscription ON payment_intents(subscription_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_intents_provider_ref ON payment_intents(provider_ref) WHERE provider_ref IS NOT NULL;
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS bucket_provisioned BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE subscriptions SET bucket_provisioned = TRUE WHERE status NOT IN ('pending', 'failed');
@@ -0,0 +1,6 @@
+UPDATE payment_intents SET status = 'captured' WHERE status = 'disputed';
+ALTER TABLE payment_intents DROP CONSTRAINT IF EXISTS payment_intents_status_check;
+ALTER TABLE payment_intents ADD CONSTRAINT payment_intents_status_check
+    CHECK (status IN ('created', 'authorized', 'captured', 'failed', 'cancelled', 'refunded'));
+
+DROP TABLE IF EXISTS webhook_events;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id TEXT PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    provider_ref TEXT NOT NULL DEFAULT '',
+    payload BYTEA NOT NULL,
+    attempts INT NOT NULL DEFAULT 1,
+    last_error TEXT NOT NULL DEFAULT '',
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_received ON webhook_events(received_at);
+
+ALTER TABLE payment_intents DROP CONSTRAINT IF EXISTS payment_intents_status_check;
+ALTER TABLE payment_intents ADD CONSTRAINT payment_intents_status_check
+    CHECK (status IN ('created', 'authorized', 'captured', 'failed', 'cancelled', 'refunded', 'disputed'));
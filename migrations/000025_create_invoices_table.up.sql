@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS tax_rates (
+    currency CHAR(3) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    rate_bp INT NOT NULL CHECK (rate_bp >= 0 AND rate_bp <= 10000)
+);
+
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    year INT PRIMARY KEY,
+    last_number INT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id BIGSERIAL PRIMARY KEY,
+    number VARCHAR(20) NOT NULL UNIQUE,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id),
+    user_id BIGINT NOT NULL,
+    payment_intent_id BIGINT REFERENCES payment_intents(id),
+    original_invoice_id BIGINT REFERENCES invoices(id),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('subscribe', 'renewal', 'proration', 'refund')),
+    status VARCHAR(20) NOT NULL
+        CHECK (status IN ('open', 'paid', 'partially_refunded', 'refunded', 'void')),
+    currency CHAR(3) NOT NULL,
+    subtotal BIGINT NOT NULL,
+    tax_name VARCHAR(50) NOT NULL DEFAULT '',
+    tax_rate_bp INT NOT NULL DEFAULT 0,
+    tax_amount BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL CHECK (total >= 0),
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    issued_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    paid_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_user ON invoices(user_id, issued_at DESC);
+CREATE INDEX IF NOT EXISTS idx_invoices_payment_intent ON invoices(payment_intent_id) WHERE payment_intent_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_invoices_open_proration ON invoices(subscription_id) WHERE kind = 'proration' AND status = 'open';
+
+CREATE TABLE IF NOT EXISTS invoice_lines (
+    id BIGSERIAL PRIMARY KEY,
+    invoice_id BIGINT NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    plan_id INT REFERENCES subscription_plans(id),
+    description TEXT NOT NULL,
+    quantity INT NOT NULL DEFAULT 1,
+    unit_amount BIGINT NOT NULL,
+    amount BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_lines_invoice ON invoice_lines(invoice_id, id);
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS refund_audits;
+
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS invoices_kind_check;
+UPDATE invoices SET kind = 'refund' WHERE kind = 'credit_note';
+ALTER TABLE invoices ADD CONSTRAINT invoices_kind_check
+    CHECK (kind IN ('subscribe', 'renewal', 'proration', 'refund'));
+
+DELETE FROM invoice_sequences WHERE series <> 'INV';
+ALTER TABLE invoice_sequences DROP CONSTRAINT IF EXISTS invoice_sequences_pkey;
+ALTER TABLE invoice_sequences ADD PRIMARY KEY (year);
+ALTER TABLE invoice_sequences DROP COLUMN IF EXISTS series;
+
+ALTER TABLE subscription_plans DROP COLUMN IF EXISTS refund_policy;
@@ -0,0 +1,31 @@
+ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS refund_policy VARCHAR(10) NOT NULL DEFAULT 'none'
+        CHECK (refund_policy IN ('none', 'partial', 'full'));
+
+ALTER TABLE invoice_sequences ADD COLUMN IF NOT EXISTS series VARCHAR(3) NOT NULL DEFAULT 'INV';
+ALTER TABLE invoice_sequences DROP CONSTRAINT IF EXISTS invoice_sequences_pkey;
+ALTER TABLE invoice_sequences ADD PRIMARY KEY (series, year);
+
+ALTER TABLE invoices DROP CONSTRAINT IF EXISTS invoices_kind_check;
+UPDATE invoices SET kind = 'credit_note' WHERE kind = 'refund';
+ALTER TABLE invoices ADD CONSTRAINT invoices_kind_check
+    CHECK (kind IN ('subscribe', 'renewal', 'proration', 'credit_note'));
+
+CREATE TABLE IF NOT EXISTS refund_audits (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id),
+    user_id BIGINT NOT NULL,
+    actor_id BIGINT NOT NULL,
+    reason_code VARCHAR(30) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    policy VARCHAR(10) NOT NULL,
+    payment_intent_id BIGINT REFERENCES payment_intents(id),
+    paid_amount BIGINT NOT NULL DEFAULT 0,
+    unused_time_bp INT NOT NULL DEFAULT 0,
+    unused_limit_bp INT NOT NULL DEFAULT 0,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_audits_subscription ON refund_audits(subscription_id, created_at DESC);
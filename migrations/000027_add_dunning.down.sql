@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS dunning_attempts;
+
+UPDATE subscriptions SET status = 'grace', grace_until = NOW() WHERE status = 'past_due';
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS past_due_since;
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused', 'grace', 'trialing', 'pending', 'failed'));
@@ -0,0 +1,23 @@
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused', 'grace', 'trialing', 'pending', 'failed', 'past_due'));
+
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS past_due_since TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS dunning_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES subscriptions(id),
+    past_due_since TIMESTAMP NOT NULL,
+    attempt INT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('scheduled', 'succeeded', 'failed', 'cancelled', 'exhausted')),
+    scheduled_at TIMESTAMP NOT NULL,
+    attempted_at TIMESTAMP,
+    payment_intent_id BIGINT REFERENCES payment_intents(id),
+    failure_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, past_due_since, attempt)
+);
+
+CREATE INDEX IF NOT EXISTS dunning_attempts_scheduled_idx
+    ON dunning_attempts (scheduled_at) WHERE status = 'scheduled';
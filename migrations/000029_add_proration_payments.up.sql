@@ -0,0 +1,3 @@
+ALTER TABLE payment_intents DROP CONSTRAINT IF EXISTS payment_intents_purpose_check;
+ALTER TABLE payment_intents ADD CONSTRAINT payment_intents_purpose_check
+    CHECK (purpose IN ('subscribe', 'renewal', 'proration'));
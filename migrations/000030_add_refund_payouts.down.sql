@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refund_audits_unpaid;
+
+ALTER TABLE refund_audits DROP COLUMN IF EXISTS paid_out_at;
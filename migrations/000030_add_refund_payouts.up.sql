@@ -0,0 +1,5 @@
+ALTER TABLE refund_audits ADD COLUMN IF NOT EXISTS paid_out_at TIMESTAMP;
+
+UPDATE refund_audits SET paid_out_at = created_at WHERE paid_out_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refund_audits_unpaid ON refund_audits(created_at) WHERE paid_out_at IS NULL;
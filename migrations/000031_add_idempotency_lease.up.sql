@@ -0,0 +1,6 @@
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS leased_until TIMESTAMP;
+
+UPDATE idempotency_keys SET leased_until = created_at WHERE leased_until IS NULL;
+
+ALTER TABLE idempotency_keys ALTER COLUMN leased_until SET DEFAULT NOW();
+ALTER TABLE idempotency_keys ALTER COLUMN leased_until SET NOT NULL;
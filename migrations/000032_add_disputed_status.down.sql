@@ -0,0 +1,5 @@
+UPDATE subscriptions SET status = 'expired', expires_at = LEAST(expires_at, NOW()) WHERE status = 'disputed';
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_status_check;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_status_check
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused', 'grace', 'trialing', 'pending', 'failed', 'past_due'));
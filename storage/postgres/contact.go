@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// GetContact returns the user's notification contact.
+func (s *Storage) GetContact(ctx context.Context, userId int64) (*data.Contact, error) {
+	query := `
+SELECT user_id, email, locale, notifications_enabled FROM user_contacts
+WHERE user_id = $1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var contact data.Contact
+	err := s.db.QueryRowContext(ctx, query, userId).Scan(
+		&contact.UserID,
+		&contact.Email,
+		&contact.Locale,
+		&contact.NotificationsEnabled,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetContact", ErrContactNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetContact", err)
+		}
+	}
+	return &contact, nil
+}
+
+// UpsertContact creates or replaces the user's notification contact.
+func (s *Storage) UpsertContact(ctx context.Context, contact *data.Contact) error {
+	query := `
+INSERT INTO user_contacts (user_id, email, locale, notifications_enabled)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET email = EXCLUDED.email,
+    locale = EXCLUDED.locale,
+    notifications_enabled = EXCLUDED.notifications_enabled,
+    updated_at = NOW()
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, contact.UserID, contact.Email, contact.Locale, contact.NotificationsEnabled)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.UpsertContact", err)
+	}
+	return nil
+}
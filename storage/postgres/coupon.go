@@ -0,0 +1,274 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"slices"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+const couponColumns = `id, code, discount_type, discount_value, COALESCE(currency, ''), bonus_limit, max_redemptions,
+       per_user_limit, valid_from, valid_until, plan_ids, disabled_at, created_at`
+
+// CreateCoupon stores a new coupon and fills in its id and creation time.
+func (s *Storage) CreateCoupon(ctx context.Context, coupon *data.Coupon) error {
+	query := `
+INSERT INTO coupons (code, discount_type, discount_value, bonus_limit, max_redemptions, per_user_limit, valid_from, valid_until, plan_ids, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''))
+RETURNING id, created_at
+`
+	planIds := make(pq.Int64Array, len(coupon.PlanIDs))
+	for i, id := range coupon.PlanIDs {
+		planIds[i] = int64(id)
+	}
+	args := []any{
+		coupon.Code,
+		coupon.DiscountType,
+		coupon.DiscountValue,
+		coupon.BonusLimit,
+		coupon.MaxRedemptions,
+		coupon.PerUserLimit,
+		nullTime(coupon.ValidFrom),
+		nullTime(coupon.ValidUntil),
+		planIds,
+		coupon.Currency,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&coupon.ID, &coupon.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return fmt.Errorf("%s:%w", "storage.postgres.CreateCoupon", ErrCouponCodeConflict)
+		}
+		return fmt.Errorf("%s:%w", "storage.postgres.CreateCoupon", err)
+	}
+	return nil
+}
+
+// DisableCoupon stops a coupon from being redeemed. Existing redemptions are
+// kept.
+func (s *Storage) DisableCoupon(ctx context.Context, code string) error {
+	query := `
+UPDATE coupons
+SET disabled_at = NOW()
+WHERE code = $1 AND disabled_at IS NULL
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, code)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.DisableCoupon", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.DisableCoupon", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", "storage.postgres.DisableCoupon", ErrCouponNotFound)
+	}
+	return nil
+}
+
+// CouponReport returns redemption totals for every coupon, newest first.
+func (s *Storage) CouponReport(ctx context.Context) ([]*data.CouponReport, error) {
+	query := `
+SELECT c.id, c.code, c.disabled_at IS NOT NULL, count(r.id), count(DISTINCT r.user_id),
+       COALESCE(sum(r.discount_amount), 0), COALESCE(sum(r.bonus_limit), 0)
+FROM coupons c
+LEFT JOIN coupon_redemptions r ON r.coupon_id = c.id
+GROUP BY c.id
+ORDER BY c.created_at DESC, c.id DESC
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.CouponReport", err)
+	}
+	defer rows.Close()
+
+	reports := []*data.CouponReport{}
+	for rows.Next() {
+		var report data.CouponReport
+		err := rows.Scan(
+			&report.CouponID,
+			&report.Code,
+			&report.Disabled,
+			&report.Redemptions,
+			&report.Users,
+			&report.TotalDiscount,
+			&report.TotalBonusLimit,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.CouponReport", err)
+		}
+		reports = append(reports, &report)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.CouponReport", err)
+	}
+	return reports, nil
+}
+
+// CheckCoupon reports whether the user could redeem code on planId right now.
+// It takes no locks, so the answer can change before the coupon is actually
+// redeemed; it exists to give callers a precise reason up front.
+func (s *Storage) CheckCoupon(ctx context.Context, code string, userId int64, planId int32) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		coupon, err := getCoupon(ctx, tx, code, false)
+		if err != nil {
+			return err
+		}
+		return checkCoupon(ctx, tx, coupon, userId, planId, time.Now())
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.CheckCoupon", err)
+	}
+	return nil
+}
+
+// redeemCoupon locks the coupon, checks that the user can still redeem it on
+// planId and records the redemption against the subscription. The discount,
+// worked out from price, is added to the subscription's discount_amount and
+// taken off its next charge; the bonus limit is credited to the balance. A
+// fixed discount in another currency than the subscription's is refused.
+func redeemCoupon(ctx context.Context, tx *sql.Tx, code string, userId, subId int64, planId int32, price int64, currency string) error {
+	coupon, err := getCoupon(ctx, tx, code, true)
+	if err != nil {
+		return err
+	}
+	err = checkCoupon(ctx, tx, coupon, userId, planId, time.Now())
+	if err != nil {
+		return err
+	}
+	if coupon.DiscountType == data.DiscountFixed && coupon.Currency != currency {
+		return ErrCouponWrongCurrency
+	}
+
+	discount := coupon.Discount(price)
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO coupon_redemptions (coupon_id, user_id, subscription_id, plan_id, discount_amount, bonus_limit)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, coupon.ID, userId, subId, planId, discount, coupon.BonusLimit)
+	if err != nil {
+		return err
+	}
+
+	var remainingLimit int64
+	err = tx.QueryRowContext(ctx, `
+UPDATE subscriptions
+SET discount_amount = discount_amount + $1, remaining_limit = remaining_limit + $2
+WHERE id = $3
+RETURNING remaining_limit
+`, discount, coupon.BonusLimit, subId).Scan(&remainingLimit)
+	if err != nil {
+		return err
+	}
+
+	_, err = insertLedgerEntry(ctx, tx, subId, int64(coupon.BonusLimit), data.AccountPromotions, data.EventCouponRedeemed, coupon.Code, remainingLimit)
+	if err != nil {
+		return err
+	}
+	return insertSubEvent(ctx, tx, subId, data.EventCouponRedeemed, discount, coupon.Code)
+}
+
+func getCoupon(ctx context.Context, tx *sql.Tx, code string, forUpdate bool) (*data.Coupon, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE code = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	var coupon data.Coupon
+	var validFrom, validUntil, disabledAt sql.NullTime
+	var planIds pq.Int64Array
+
+	err := tx.QueryRowContext(ctx, query, code).Scan(
+		&coupon.ID,
+		&coupon.Code,
+		&coupon.DiscountType,
+		&coupon.DiscountValue,
+		&coupon.Currency,
+		&coupon.BonusLimit,
+		&coupon.MaxRedemptions,
+		&coupon.PerUserLimit,
+		&validFrom,
+		&validUntil,
+		&planIds,
+		&disabledAt,
+		&coupon.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCouponNotFound
+		}
+		return nil, err
+	}
+	coupon.ValidFrom = validFrom.Time
+	coupon.ValidUntil = validUntil.Time
+	coupon.DisabledAt = disabledAt.Time
+	for _, id := range planIds {
+		coupon.PlanIDs = append(coupon.PlanIDs, int32(id))
+	}
+	return &coupon, nil
+}
+
+// checkCoupon returns the reason the user cannot redeem coupon on planId at
+// now, or nil if they can.
+func checkCoupon(ctx context.Context, tx *sql.Tx, coupon *data.Coupon, userId int64, planId int32, now time.Time) error {
+	switch {
+	case !coupon.DisabledAt.IsZero():
+		return ErrCouponDisabled
+	case !coupon.ValidFrom.IsZero() && now.Before(coupon.ValidFrom):
+		return ErrCouponNotActive
+	case !coupon.ValidUntil.IsZero() && !now.Before(coupon.ValidUntil):
+		return ErrCouponNotActive
+	case len(coupon.PlanIDs) > 0 && !slices.Contains(coupon.PlanIDs, planId):
+		return ErrCouponWrongPlan
+	}
+
+	var total, byUser int32
+	err := tx.QueryRowContext(ctx, `
+SELECT count(*), count(*) FILTER (WHERE user_id = $2)
+FROM coupon_redemptions
+WHERE coupon_id = $1
+`, coupon.ID, userId).Scan(&total, &byUser)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case coupon.MaxRedemptions > 0 && total >= coupon.MaxRedemptions:
+		return ErrCouponExhausted
+	case byUser >= coupon.PerUserLimit:
+		return ErrCouponAlreadyUsed
+	}
+	return nil
+}
+
+// IsCouponError reports whether err means a coupon could not be redeemed.
+func IsCouponError(err error) bool {
+	return errors.Is(err, ErrCouponNotFound) ||
+		errors.Is(err, ErrCouponDisabled) ||
+		errors.Is(err, ErrCouponNotActive) ||
+		errors.Is(err, ErrCouponWrongPlan) ||
+		errors.Is(err, ErrCouponWrongCurrency) ||
+		errors.Is(err, ErrCouponExhausted) ||
+		errors.Is(err, ErrCouponAlreadyUsed)
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
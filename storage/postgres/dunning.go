@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// BeginDunning moves up to limit subscriptions whose grace period has run out
+// without a successful renewal to past due. The dunning cycle is measured from
+// the end of the paid period, and the last failed renewal charge is recorded
+// as its first attempt. Rows locked by another replica are skipped.
+func (s *Storage) BeginDunning(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error) {
+	query := `
+UPDATE subscriptions
+SET status = 'past_due', grace_until = NULL, past_due_since = expires_at
+WHERE id IN (
+    SELECT s.id FROM subscriptions s
+    WHERE s.status = 'grace' AND s.grace_until <= $1
+    ORDER BY s.grace_until
+    LIMIT $2
+    FOR UPDATE OF s SKIP LOCKED
+)
+RETURNING id, user_id, plan_id, remaining_limit, expires_at, status
+`
+	attemptQuery := `
+INSERT INTO dunning_attempts (subscription_id, past_due_since, attempt, status, scheduled_at, attempted_at,
+                              payment_intent_id, failure_reason)
+SELECT s.id, s.past_due_since, 0, 'failed', s.past_due_since, $2, pi.id, COALESCE(pi.failure_reason, '')
+FROM subscriptions s
+LEFT JOIN LATERAL (
+    SELECT id, failure_reason FROM payment_intents
+    WHERE subscription_id = s.id AND purpose = 'renewal' AND status = 'failed'
+    ORDER BY id DESC
+    LIMIT 1
+) pi ON TRUE
+WHERE s.id = $1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var pastDue []data.Subscription
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		pastDue, err = transitionSubscriptions(ctx, tx, data.EventPastDue, "", query, now, limit)
+		if err != nil {
+			return err
+		}
+		for _, sub := range pastDue {
+			if _, err := tx.ExecContext(ctx, attemptQuery, sub.ID, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.BeginDunning", err)
+	}
+	return pastDue, nil
+}
+
+// ScheduleDunning picks one past due subscription with no retry pending and
+// schedules its next retry, schedule[n-1] after it became past due for the
+// n-th retry, or now if that moment has already passed. When every retry in
+// schedule has been tried, the subscription expires instead and the returned
+// attempt is the exhausted one. Rows locked by another replica are skipped.
+// It returns nil when nothing needs scheduling.
+func (s *Storage) ScheduleDunning(ctx context.Context, now time.Time, schedule []time.Duration) (*data.DunningAttempt, error) {
+	query := `
+SELECT s.id, s.user_id, s.past_due_since,
+       (SELECT count(*) FROM dunning_attempts da
+        WHERE da.subscription_id = s.id AND da.past_due_since = s.past_due_since)
+FROM subscriptions s
+WHERE s.status = 'past_due'
+  AND NOT EXISTS (
+    SELECT 1 FROM dunning_attempts da
+    WHERE da.subscription_id = s.id AND da.past_due_since = s.past_due_since AND da.status = 'scheduled'
+  )
+ORDER BY s.past_due_since
+LIMIT 1
+FOR UPDATE OF s SKIP LOCKED
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var attempt *data.DunningAttempt
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		a := data.DunningAttempt{Status: data.DunningScheduled, ScheduledAt: now}
+
+		err := tx.QueryRowContext(ctx, query).Scan(&a.SubscriptionID, &a.UserID, &a.PastDueSince, &a.Attempt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		if int(a.Attempt) > len(schedule) {
+			a.Status = data.DunningExhausted
+			_, err = tx.ExecContext(ctx, `
+UPDATE subscriptions SET status = 'expired', past_due_since = NULL WHERE id = $1
+`, a.SubscriptionID)
+			if err != nil {
+				return err
+			}
+			if err := insertDunningAttempt(ctx, tx, &a, now); err != nil {
+				return err
+			}
+			if err := insertSubEvent(ctx, tx, a.SubscriptionID, data.EventExpired, 0, data.DunningGaveUp); err != nil {
+				return err
+			}
+			attempt = &a
+			return nil
+		}
+
+		if due := a.PastDueSince.Add(schedule[a.Attempt-1]); due.After(now) {
+			a.ScheduledAt = due
+		}
+		if err := insertDunningAttempt(ctx, tx, &a, time.Time{}); err != nil {
+			return err
+		}
+		err = insertSubEvent(ctx, tx, a.SubscriptionID, data.EventDunningScheduled, 0, a.ScheduledAt.Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+		attempt = &a
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ScheduleDunning", err)
+	}
+	return attempt, nil
+}
+
+// insertDunningAttempt records a in the subscription's current cycle.
+// attemptedAt is left NULL when zero.
+func insertDunningAttempt(ctx context.Context, tx *sql.Tx, a *data.DunningAttempt, attemptedAt time.Time) error {
+	a.AttemptedAt = attemptedAt
+	return tx.QueryRowContext(ctx, `
+INSERT INTO dunning_attempts (subscription_id, past_due_since, attempt, status, scheduled_at, attempted_at,
+                              payment_intent_id, failure_reason)
+VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), $8)
+RETURNING id
+`, a.SubscriptionID, a.PastDueSince, a.Attempt, a.Status, a.ScheduledAt, nullTime(attemptedAt),
+		a.PaymentIntentID, a.FailureReason).Scan(&a.ID)
+}
+
+// settleDunningAttempt records the outcome of a renewal charge against the
+// retry that was pending for the subscription, if any.
+func settleDunningAttempt(ctx context.Context, tx *sql.Tx, subId, intentId int64, status, reason string, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+UPDATE dunning_attempts
+SET status = $2, payment_intent_id = $3, failure_reason = $4, attempted_at = $5
+WHERE subscription_id = $1 AND status = 'scheduled'
+`, subId, status, intentId, reason, now)
+	return err
+}
+
+// cancelDunning drops the retries still pending for the subscription, when
+// it leaves the past due status other than by paying.
+func cancelDunning(ctx context.Context, tx *sql.Tx, subId int64) error {
+	_, err := tx.ExecContext(ctx, `
+UPDATE dunning_attempts SET status = 'cancelled'
+WHERE subscription_id = $1 AND status = 'scheduled'
+`, subId)
+	return err
+}
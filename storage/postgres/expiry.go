@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// ExpireSubscriptions moves up to limit subscriptions that are past their paid
+// period into the expired status: active ones on plans that do not auto-renew,
+// cancelled ones and disputed ones. Auto-renewing subscriptions are left to the renewal
+// engine and to dunning. Rows locked by another replica are skipped, so
+// several workers can run this concurrently without clashing.
+func (s *Storage) ExpireSubscriptions(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error) {
+	query := `
+UPDATE subscriptions
+SET status = 'expired'
+WHERE id IN (
+    SELECT s.id FROM subscriptions s
+    JOIN subscription_plans p ON p.id = s.plan_id
+    WHERE (s.status = 'active' AND NOT p.auto_renew AND s.expires_at <= $1)
+       OR (s.status IN ('cancelled', 'disputed') AND s.expires_at <= $1)
+    ORDER BY s.expires_at
+    LIMIT $2
+    FOR UPDATE OF s SKIP LOCKED
+)
+RETURNING id, user_id, plan_id, remaining_limit, expires_at, status
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var expired []data.Subscription
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		expired, err = transitionSubscriptions(ctx, tx, data.EventExpired, "", query, now, limit)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ExpireSubscriptions", err)
+	}
+	return expired, nil
+}
+
+// transitionSubscriptions runs a bulk status update that returns the changed
+// rows and records eventType in the history for each of them.
+func transitionSubscriptions(ctx context.Context, tx *sql.Tx, eventType, details, query string, args ...any) ([]data.Subscription, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changed := []data.Subscription{}
+	for rows.Next() {
+		var sub data.Subscription
+		err := rows.Scan(
+			&sub.ID,
+			&sub.UserID,
+			&sub.PlanID,
+			&sub.RemainingLimit,
+			&sub.ExpiresAt,
+			&sub.Status,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, sub := range changed {
+		if err := insertSubEvent(ctx, tx, sub.ID, eventType, 0, details); err != nil {
+			return nil, err
+		}
+	}
+	return changed, nil
+}
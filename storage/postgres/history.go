@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// insertSubEvent appends a history record for the subscription, snapshotting
+// its current row, and queues the same record in the outbox for publishing.
+// It must run in the transaction that made the change.
+func insertSubEvent(ctx context.Context, tx *sql.Tx, subId int64, eventType string, amount int64, details string) error {
+	query := `
+WITH event AS (
+    INSERT INTO subscription_events (subscription_id, user_id, event_type, plan_id, status, remaining_limit, expires_at, amount, details)
+    SELECT id, user_id, $2, plan_id, status, remaining_limit, expires_at, $3, $4
+    FROM subscriptions
+    WHERE id = $1
+    RETURNING *
+)
+INSERT INTO outbox_events (event_id, subscription_id, user_id, event_type, payload)
+SELECT id, subscription_id, user_id, event_type, json_build_object(
+    'event_id', id,
+    'subscription_id', subscription_id,
+    'user_id', user_id,
+    'event_type', event_type,
+    'plan_id', plan_id,
+    'status', status,
+    'remaining_limit', remaining_limit,
+    'expires_at', expires_at,
+    'amount', amount,
+    'details', details,
+    'occurred_at', created_at
+)
+FROM event
+`
+	_, err := tx.ExecContext(ctx, query, subId, eventType, amount, details)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.insertSubEvent", err)
+	}
+	return nil
+}
+
+// ListSubscriptionHistory returns a page of the user's subscription events,
+// newest first, together with the total number of events.
+func (s *Storage) ListSubscriptionHistory(ctx context.Context, userId int64, filters data.Filters) ([]*data.SubEvent, int, error) {
+	query := `
+SELECT count(*) OVER(), id, subscription_id, user_id, event_type, COALESCE(plan_id, 0), status,
+       remaining_limit, expires_at, amount, details, created_at
+FROM subscription_events
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userId, filters.Limit(), filters.Offset())
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListSubscriptionHistory", err)
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	events := []*data.SubEvent{}
+
+	for rows.Next() {
+		var event data.SubEvent
+
+		err := rows.Scan(
+			&totalRecords,
+			&event.ID,
+			&event.SubscriptionID,
+			&event.UserID,
+			&event.Type,
+			&event.PlanID,
+			&event.Status,
+			&event.RemainingLimit,
+			&event.ExpiresAt,
+			&event.Amount,
+			&event.Details,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListSubscriptionHistory", err)
+		}
+
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListSubscriptionHistory", err)
+	}
+
+	return events, totalRecords, nil
+}
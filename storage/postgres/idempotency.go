@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// ClaimIdempotencyKey reserves key for the user for lease. It reports true
+// when the key was free, had outlived retention, or was left without a
+// response by a request whose lease ran out without being renewed, and is now
+// owned by the caller. Otherwise it returns the record already stored under
+// the key.
+func (s *Storage) ClaimIdempotencyKey(ctx context.Context, userId int64, key, method string, requestHash []byte, retention, lease time.Duration) (*data.IdempotencyRecord, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2
+  AND (created_at <= NOW() - $3 * INTERVAL '1 second'
+    OR (response IS NULL AND leased_until <= NOW()))
+`, userId, key, retention.Seconds())
+	if err != nil {
+		return nil, false, fmt.Errorf("%s:%w", "storage.postgres.ClaimIdempotencyKey", err)
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+INSERT INTO idempotency_keys (user_id, key, method, request_hash, leased_until)
+VALUES ($1, $2, $3, $4, NOW() + $5 * INTERVAL '1 second')
+ON CONFLICT (user_id, key) DO NOTHING
+`, userId, key, method, requestHash, lease.Seconds())
+	if err != nil {
+		return nil, false, fmt.Errorf("%s:%w", "storage.postgres.ClaimIdempotencyKey", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s:%w", "storage.postgres.ClaimIdempotencyKey", err)
+	}
+	if rowsAffected == 1 {
+		return nil, true, nil
+	}
+
+	record := data.IdempotencyRecord{UserID: userId, Key: key}
+	var header []byte
+	err = s.db.QueryRowContext(ctx, `
+SELECT method, request_hash, response, response_header, created_at
+FROM idempotency_keys
+WHERE user_id = $1 AND key = $2
+`, userId, key).Scan(&record.Method, &record.RequestHash, &record.Response, &header, &record.CreatedAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s:%w", "storage.postgres.ClaimIdempotencyKey", err)
+	}
+	if header != nil {
+		if err := json.Unmarshal(header, &record.ResponseHeader); err != nil {
+			return nil, false, fmt.Errorf("%s:%w", "storage.postgres.ClaimIdempotencyKey", err)
+		}
+	}
+	return &record, false, nil
+}
+
+// RenewIdempotencyKey extends the lease on a claimed key whose request is
+// still running, so that retries keep failing until it has finished.
+func (s *Storage) RenewIdempotencyKey(ctx context.Context, userId int64, key string, lease time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `
+UPDATE idempotency_keys
+SET leased_until = NOW() + $3 * INTERVAL '1 second'
+WHERE user_id = $1 AND key = $2 AND response IS NULL
+`, userId, key, lease.Seconds())
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.RenewIdempotencyKey", err)
+	}
+	return nil
+}
+
+// SaveIdempotentResponse stores the response and its header for a claimed key
+// so duplicates can replay them.
+func (s *Storage) SaveIdempotentResponse(ctx context.Context, userId int64, key string, response []byte, header map[string][]string) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	encodedHeader, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.SaveIdempotentResponse", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+UPDATE idempotency_keys
+SET response = $3, response_header = $4
+WHERE user_id = $1 AND key = $2
+`, userId, key, response, encodedHeader)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.SaveIdempotentResponse", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey drops a claim whose request failed, so the client can
+// retry with the same key.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, userId int64, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2 AND response IS NULL
+`, userId, key)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.ReleaseIdempotencyKey", err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes keys created before the cutoff.
+func (s *Storage) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at <= $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", "storage.postgres.DeleteExpiredIdempotencyKeys", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,369 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+const invoiceColumns = `id, number, subscription_id, user_id, COALESCE(payment_intent_id, 0), COALESCE(original_invoice_id, 0),
+       kind, status, currency, subtotal, tax_name, tax_rate_bp, tax_amount, total, period_start, period_end,
+       issued_at, paid_at`
+
+func scanInvoice(row interface{ Scan(...any) error }) (*data.Invoice, error) {
+	var invoice data.Invoice
+	var paidAt sql.NullTime
+	err := row.Scan(
+		&invoice.ID,
+		&invoice.Number,
+		&invoice.SubscriptionID,
+		&invoice.UserID,
+		&invoice.PaymentIntentID,
+		&invoice.OriginalInvoiceID,
+		&invoice.Kind,
+		&invoice.Status,
+		&invoice.Currency,
+		&invoice.Subtotal,
+		&invoice.TaxName,
+		&invoice.TaxRate,
+		&invoice.TaxAmount,
+		&invoice.Total,
+		&invoice.PeriodStart,
+		&invoice.PeriodEnd,
+		&invoice.IssuedAt,
+		&paidAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	invoice.PaidAt = paidAt.Time
+	return &invoice, nil
+}
+
+// countedRow scans a row whose first column is a count(*) OVER() into count
+// and the rest into the destinations it is given.
+type countedRow struct {
+	row   interface{ Scan(...any) error }
+	count *int
+}
+
+func (r countedRow) Scan(dest ...any) error {
+	return r.row.Scan(append([]any{r.count}, dest...)...)
+}
+
+// ListInvoices returns a page of the user's invoices, newest first, without
+// their lines.
+func (s *Storage) ListInvoices(ctx context.Context, userId int64, filters data.Filters) ([]*data.Invoice, int, error) {
+	query := `
+SELECT count(*) OVER(), ` + invoiceColumns + `
+FROM invoices
+WHERE user_id = $1
+ORDER BY issued_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userId, filters.Limit(), filters.Offset())
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListInvoices", err)
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	invoices := []*data.Invoice{}
+
+	for rows.Next() {
+		invoice, err := scanInvoice(countedRow{rows, &totalRecords})
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListInvoices", err)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListInvoices", err)
+	}
+	return invoices, totalRecords, nil
+}
+
+// GetInvoice returns an invoice with its lines by number, or
+// ErrInvoiceNotFound.
+func (s *Storage) GetInvoice(ctx context.Context, number string) (*data.Invoice, error) {
+	linesQuery := `
+SELECT id, COALESCE(plan_id, 0), description, quantity, unit_amount, amount
+FROM invoice_lines
+WHERE invoice_id = $1
+ORDER BY id
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	invoice, err := scanInvoice(s.db.QueryRowContext(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE number = $1`, number))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetInvoice", ErrInvoiceNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetInvoice", err)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, linesQuery, invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.GetInvoice", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line data.InvoiceLine
+		err := rows.Scan(&line.ID, &line.PlanID, &line.Description, &line.Quantity, &line.UnitAmount, &line.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetInvoice", err)
+		}
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.GetInvoice", err)
+	}
+	return invoice, nil
+}
+
+// issueInvoice numbers and stores invoice with its lines. The total is the
+// sum of the lines and the tax is worked out from the rate for the currency,
+// except on credit notes, which keep the rate of the invoice they credit.
+// Numbers are taken from a per-series, per-year counter whose row stays locked
+// until the transaction ends, so a rolled back invoice gives its number back
+// and the sequence has no gaps.
+func issueInvoice(ctx context.Context, tx *sql.Tx, invoice *data.Invoice) error {
+	invoice.Total = 0
+	for _, line := range invoice.Lines {
+		invoice.Total += line.Amount
+	}
+
+	if invoice.Kind != data.InvoiceCreditNote {
+		err := tx.QueryRowContext(ctx, `SELECT name, rate_bp FROM tax_rates WHERE currency = $1`, invoice.Currency).Scan(&invoice.TaxName, &invoice.TaxRate)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+	invoice.TaxAmount = data.IncludedTax(invoice.Total, invoice.TaxRate)
+	invoice.Subtotal = invoice.Total - invoice.TaxAmount
+
+	now := time.Now()
+	var seq int
+	err := tx.QueryRowContext(ctx, `
+INSERT INTO invoice_sequences (series, year, last_number)
+VALUES ($1, $2, 1)
+ON CONFLICT (series, year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+RETURNING last_number
+`, invoice.Series(), now.Year()).Scan(&seq)
+	if err != nil {
+		return err
+	}
+	invoice.Number = data.InvoiceNumber(invoice.Series(), now.Year(), seq)
+
+	var paymentIntentId, originalInvoiceId sql.NullInt64
+	if invoice.PaymentIntentID != 0 {
+		paymentIntentId = sql.NullInt64{Int64: invoice.PaymentIntentID, Valid: true}
+	}
+	if invoice.OriginalInvoiceID != 0 {
+		originalInvoiceId = sql.NullInt64{Int64: invoice.OriginalInvoiceID, Valid: true}
+	}
+	if invoice.Status == data.InvoicePaid {
+		invoice.PaidAt = now
+	}
+
+	query := `
+INSERT INTO invoices (number, subscription_id, user_id, payment_intent_id, original_invoice_id, kind, status, currency,
+                      subtotal, tax_name, tax_rate_bp, tax_amount, total, period_start, period_end, issued_at, paid_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+RETURNING id, issued_at
+`
+	args := []any{
+		invoice.Number,
+		invoice.SubscriptionID,
+		invoice.UserID,
+		paymentIntentId,
+		originalInvoiceId,
+		invoice.Kind,
+		invoice.Status,
+		invoice.Currency,
+		invoice.Subtotal,
+		invoice.TaxName,
+		invoice.TaxRate,
+		invoice.TaxAmount,
+		invoice.Total,
+		invoice.PeriodStart,
+		invoice.PeriodEnd,
+		now,
+		nullTime(invoice.PaidAt),
+	}
+	err = tx.QueryRowContext(ctx, query, args...).Scan(&invoice.ID, &invoice.IssuedAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		var planId sql.NullInt32
+		if line.PlanID != 0 {
+			planId = sql.NullInt32{Int32: line.PlanID, Valid: true}
+		}
+		err := tx.QueryRowContext(ctx, `
+INSERT INTO invoice_lines (invoice_id, plan_id, description, quantity, unit_amount, amount)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id
+`, invoice.ID, planId, line.Description, line.Quantity, line.UnitAmount, line.Amount).Scan(&line.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planLine returns an invoice line for amount of planId over a period.
+func planLine(ctx context.Context, tx *sql.Tx, planId int32, amount int64, start, end time.Time) (data.InvoiceLine, error) {
+	var name string
+	err := tx.QueryRowContext(ctx, `SELECT name FROM subscription_plans WHERE id = $1`, planId).Scan(&name)
+	if err != nil {
+		return data.InvoiceLine{}, err
+	}
+	return data.InvoiceLine{
+		PlanID:      planId,
+		Description: fmt.Sprintf("%s, %s – %s", name, start.Format("2006-01-02"), end.Format("2006-01-02")),
+		Quantity:    1,
+		UnitAmount:  amount,
+		Amount:      amount,
+	}, nil
+}
+
+// issuePaymentInvoice issues the paid invoice for an applied subscribe or
+// renewal intent, covering the subscription's current period. Of what the
+// intent charged, outstanding was owed from prorated upgrades; it is settled
+// by marking the subscription's open proration invoices paid instead of
+// being invoiced again. The coupon discount is taken off the plan line.
+func issuePaymentInvoice(ctx context.Context, tx *sql.Tx, intent *data.PaymentIntent, kind string, outstanding int64) error {
+	invoice := data.Invoice{
+		SubscriptionID:  intent.SubscriptionID,
+		UserID:          intent.UserID,
+		PaymentIntentID: intent.ID,
+		Kind:            kind,
+		Status:          data.InvoicePaid,
+		Currency:        intent.Currency,
+	}
+	err := tx.QueryRowContext(ctx, `SELECT period_started_at, expires_at FROM subscriptions WHERE id = $1`, intent.SubscriptionID).Scan(&invoice.PeriodStart, &invoice.PeriodEnd)
+	if err != nil {
+		return err
+	}
+
+	planAmount := intent.Amount + intent.DiscountApplied - outstanding
+	line, err := planLine(ctx, tx, intent.PlanID, planAmount, invoice.PeriodStart, invoice.PeriodEnd)
+	if err != nil {
+		return err
+	}
+	invoice.Lines = append(invoice.Lines, line)
+	if discount := min(intent.DiscountApplied, planAmount); discount > 0 {
+		invoice.Lines = append(invoice.Lines, data.InvoiceLine{
+			Description: "Coupon discount",
+			Quantity:    1,
+			UnitAmount:  -discount,
+			Amount:      -discount,
+		})
+	}
+	if err := issueInvoice(ctx, tx, &invoice); err != nil {
+		return err
+	}
+
+	if outstanding == 0 {
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, `
+UPDATE invoices
+SET status = 'paid', paid_at = NOW(), payment_intent_id = $2, updated_at = NOW()
+WHERE subscription_id = $1 AND kind = 'proration' AND status = 'open'
+`, intent.SubscriptionID, intent.ID)
+	return err
+}
+
+// issueCreditNote credits amount of the invoice paid by intent, once the
+// refund has been recorded on the intent, and marks that invoice refunded in
+// full or in part. A payment that was never invoiced, because its
+// subscription or plan change was never applied, gets no credit note.
+func issueCreditNote(ctx context.Context, tx *sql.Tx, intent *data.PaymentIntent, amount int64) error {
+	original, err := scanInvoice(tx.QueryRowContext(ctx, `
+SELECT `+invoiceColumns+`
+FROM invoices
+WHERE payment_intent_id = $1 AND kind = $2
+FOR UPDATE
+`, intent.ID, intent.Purpose))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	status := data.InvoicePartiallyRefunded
+	if intent.RefundedAmount == intent.Amount {
+		status = data.InvoiceRefunded
+	}
+	if !data.CanTransitionInvoice(original.Status, status) {
+		return ErrInvalidInvoiceTransition
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE invoices SET status = $2, updated_at = NOW() WHERE id = $1`, original.ID, status)
+	if err != nil {
+		return err
+	}
+
+	creditNote := data.Invoice{
+		SubscriptionID:    original.SubscriptionID,
+		UserID:            original.UserID,
+		PaymentIntentID:   intent.ID,
+		OriginalInvoiceID: original.ID,
+		Kind:              data.InvoiceCreditNote,
+		Status:            data.InvoicePaid,
+		Currency:          original.Currency,
+		TaxName:           original.TaxName,
+		TaxRate:           original.TaxRate,
+		PeriodStart:       original.PeriodStart,
+		PeriodEnd:         original.PeriodEnd,
+		Lines: []data.InvoiceLine{{
+			Description: "Credit for invoice " + original.Number,
+			Quantity:    1,
+			UnitAmount:  amount,
+			Amount:      amount,
+		}},
+	}
+	return issueInvoice(ctx, tx, &creditNote)
+}
+
+// issueProrationInvoice issues the paid invoice for an applied proration
+// intent, covering the rest of the current period on the new plan.
+func issueProrationInvoice(ctx context.Context, tx *sql.Tx, intent *data.PaymentIntent) error {
+	invoice := data.Invoice{
+		SubscriptionID:  intent.SubscriptionID,
+		UserID:          intent.UserID,
+		PaymentIntentID: intent.ID,
+		Kind:            data.InvoiceProration,
+		Status:          data.InvoicePaid,
+		Currency:        intent.Currency,
+		PeriodStart:     time.Now(),
+	}
+	err := tx.QueryRowContext(ctx, `SELECT expires_at FROM subscriptions WHERE id = $1`, intent.SubscriptionID).Scan(&invoice.PeriodEnd)
+	if err != nil {
+		return err
+	}
+
+	line, err := planLine(ctx, tx, intent.PlanID, intent.Amount, invoice.PeriodStart, invoice.PeriodEnd)
+	if err != nil {
+		return err
+	}
+	line.Description = "Prorated upgrade to " + line.Description
+	invoice.Lines = []data.InvoiceLine{line}
+	return issueInvoice(ctx, tx, &invoice)
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// insertLedgerEntry records a movement of delta rental limit between the
+// subscription balance and counterAccount. Positive deltas are credits,
+// negative ones debits; a zero delta records nothing. It returns the id of the
+// new entry, or zero when nothing was recorded.
+func insertLedgerEntry(ctx context.Context, tx *sql.Tx, subId int64, delta int64, counterAccount, reason, reference string, balanceAfter int64) (int64, error) {
+	if delta == 0 {
+		return 0, nil
+	}
+
+	entryType, amount := "credit", delta
+	if delta < 0 {
+		entryType, amount = "debit", -delta
+	}
+
+	query := `
+INSERT INTO balance_transactions (subscription_id, user_id, entry_type, amount, counter_account, reason, reference, balance_after)
+SELECT id, user_id, $2, $3, $4, $5, $6, $7
+FROM subscriptions
+WHERE id = $1
+RETURNING id
+`
+	var id int64
+	err := tx.QueryRowContext(ctx, query, subId, entryType, amount, counterAccount, reason, reference, balanceAfter).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s:%w", "storage.postgres.insertLedgerEntry", err)
+	}
+	return id, nil
+}
+
+// ListBalanceTransactions returns a page of the user's ledger entries, newest
+// first, together with the total number of entries.
+func (s *Storage) ListBalanceTransactions(ctx context.Context, userId int64, filters data.Filters) ([]*data.BalanceTransaction, int, error) {
+	query := `
+SELECT count(*) OVER(), id, subscription_id, user_id, entry_type, amount, counter_account, reason, reference, balance_after, created_at
+FROM balance_transactions
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userId, filters.Limit(), filters.Offset())
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListBalanceTransactions", err)
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	transactions := []*data.BalanceTransaction{}
+
+	for rows.Next() {
+		var t data.BalanceTransaction
+
+		err := rows.Scan(
+			&totalRecords,
+			&t.ID,
+			&t.SubscriptionID,
+			&t.UserID,
+			&t.EntryType,
+			&t.Amount,
+			&t.CounterAccount,
+			&t.Reason,
+			&t.Reference,
+			&t.BalanceAfter,
+			&t.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListBalanceTransactions", err)
+		}
+
+		transactions = append(transactions, &t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("%s:%w", "storage.postgres.ListBalanceTransactions", err)
+	}
+
+	return transactions, totalRecords, nil
+}
+
+// FindBalanceDrift returns every subscription whose remaining_limit differs
+// from the balance derived from its ledger entries.
+func (s *Storage) FindBalanceDrift(ctx context.Context) ([]data.BalanceDrift, error) {
+	query := `
+SELECT s.id, s.user_id, s.remaining_limit,
+       COALESCE(SUM(CASE WHEN t.entry_type = 'credit' THEN t.amount ELSE -t.amount END), 0) AS ledger_balance
+FROM subscriptions s
+LEFT JOIN balance_transactions t ON t.subscription_id = s.id
+GROUP BY s.id, s.user_id, s.remaining_limit
+HAVING s.remaining_limit <> COALESCE(SUM(CASE WHEN t.entry_type = 'credit' THEN t.amount ELSE -t.amount END), 0)
+ORDER BY s.id
+`
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.FindBalanceDrift", err)
+	}
+	defer rows.Close()
+
+	drifts := []data.BalanceDrift{}
+	for rows.Next() {
+		var d data.BalanceDrift
+		err := rows.Scan(&d.SubscriptionID, &d.UserID, &d.RemainingLimit, &d.LedgerBalance)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.FindBalanceDrift", err)
+		}
+		drifts = append(drifts, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.FindBalanceDrift", err)
+	}
+	return drifts, nil
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// ClaimOutboxMessages leases up to limit unpublished messages that are due,
+// oldest first, by pushing their next attempt lease into the future. A
+// message whose publisher crashes becomes due again once the lease runs out,
+// which gives at-least-once delivery. Rows leased by another replica are
+// skipped.
+func (s *Storage) ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]data.OutboxMessage, error) {
+	query := `
+UPDATE outbox_events
+SET next_attempt_at = $1 + $2 * INTERVAL '1 second', attempts = attempts + 1
+WHERE id IN (
+    SELECT id FROM outbox_events
+    WHERE published_at IS NULL AND next_attempt_at <= $1
+    ORDER BY id
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, event_id, subscription_id, user_id, event_type, payload, attempts, created_at
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, now, lease.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ClaimOutboxMessages", err)
+	}
+	defer rows.Close()
+
+	messages := []data.OutboxMessage{}
+	for rows.Next() {
+		var msg data.OutboxMessage
+		err := rows.Scan(
+			&msg.ID,
+			&msg.EventID,
+			&msg.SubscriptionID,
+			&msg.UserID,
+			&msg.EventType,
+			&msg.Payload,
+			&msg.Attempts,
+			&msg.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.ClaimOutboxMessages", err)
+		}
+		messages = append(messages, msg)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ClaimOutboxMessages", err)
+	}
+	return messages, nil
+}
+
+// MarkOutboxPublished records that the message was delivered.
+func (s *Storage) MarkOutboxPublished(ctx context.Context, id int64) error {
+	query := `
+UPDATE outbox_events
+SET published_at = NOW(), last_error = ''
+WHERE id = $1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.MarkOutboxPublished", err)
+	}
+	return nil
+}
+
+// MarkOutboxFailed records a failed delivery and when to try again.
+func (s *Storage) MarkOutboxFailed(ctx context.Context, id int64, retryAt time.Time, reason string) error {
+	query := `
+UPDATE outbox_events
+SET next_attempt_at = $2, last_error = $3
+WHERE id = $1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, id, retryAt, reason)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.MarkOutboxFailed", err)
+	}
+	return nil
+}
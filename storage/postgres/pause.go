@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// PauseSubscription freezes an active subscription. Plans with max_pause_days
+// of zero cannot be paused.
+func (s *Storage) PauseSubscription(ctx context.Context, userId int64) subs.Status {
+	query := `
+SELECT s.status, s.expires_at, p.max_pause_days
+FROM subscriptions s
+JOIN subscription_plans p ON p.id = s.plan_id
+WHERE s.user_id = $1
+FOR UPDATE OF s
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	opStatus := subs.Status_STATUS_OK
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var subStatus string
+		var expiresAt time.Time
+		var maxPauseDays int32
+
+		err := tx.QueryRowContext(ctx, query, userId).Scan(&subStatus, &expiresAt, &maxPauseDays)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				opStatus = subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+				return nil
+			}
+			return err
+		}
+
+		switch {
+		case subStatus != "active" || !time.Now().Before(expiresAt):
+			opStatus = subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+			return nil
+		case maxPauseDays == 0:
+			opStatus = subs.Status_STATUS_INVALID_PLAN
+			return nil
+		}
+
+		var subId int64
+		err = tx.QueryRowContext(ctx, `
+UPDATE subscriptions
+SET status = 'paused', paused_at = NOW()
+WHERE user_id = $1
+RETURNING id
+`, userId).Scan(&subId)
+		if err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventPaused, 0, "")
+	})
+	if err != nil {
+		println(err.Error())
+		return subs.Status_STATUS_INTERNAL_ERROR
+	}
+	return opStatus
+}
+
+// ResumeSubscription reactivates a paused subscription and shifts expires_at
+// forward by the time spent paused, capped at the plan's max_pause_days.
+func (s *Storage) ResumeSubscription(ctx context.Context, userId int64) (subs.Status, time.Time) {
+	query := `
+UPDATE subscriptions s
+SET status = 'active',
+    expires_at = s.expires_at + LEAST(NOW() - s.paused_at, p.max_pause_days * INTERVAL '1 day'),
+    paused_at = NULL
+FROM subscription_plans p
+WHERE p.id = s.plan_id AND s.user_id = $1 AND s.status = 'paused'
+RETURNING s.id, s.expires_at
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var expiresAt time.Time
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var subId int64
+		err := tx.QueryRowContext(ctx, query, userId).Scan(&subId, &expiresAt)
+		if err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventResumed, 0, "")
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return subs.Status_STATUS_SUBSCRIPTION_NOTFOUND, expiresAt
+		default:
+			println(err.Error())
+			return subs.Status_STATUS_INTERNAL_ERROR, expiresAt
+		}
+	}
+	return subs.Status_STATUS_OK, expiresAt
+}
+
+// ResumeOverduePauses resumes up to limit subscriptions that have been paused
+// for longer than their plan allows.
+func (s *Storage) ResumeOverduePauses(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error) {
+	query := `
+UPDATE subscriptions s
+SET status = 'active',
+    expires_at = s.expires_at + p.max_pause_days * INTERVAL '1 day',
+    paused_at = NULL
+FROM subscription_plans p
+WHERE p.id = s.plan_id AND s.id IN (
+    SELECT ps.id FROM subscriptions ps
+    JOIN subscription_plans pp ON pp.id = ps.plan_id
+    WHERE ps.status = 'paused' AND ps.paused_at + pp.max_pause_days * INTERVAL '1 day' <= $1
+    ORDER BY ps.paused_at
+    LIMIT $2
+    FOR UPDATE OF ps SKIP LOCKED
+)
+RETURNING s.id, s.user_id, s.plan_id, s.remaining_limit, s.expires_at, s.status
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var resumed []data.Subscription
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		resumed, err = transitionSubscriptions(ctx, tx, data.EventResumed, "pause limit reached", query, now, limit)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ResumeOverduePauses", err)
+	}
+	return resumed, nil
+}
@@ -0,0 +1,400 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+const paymentIntentColumns = `id, subscription_id, user_id, plan_id, purpose, amount, discount_applied, currency, status,
+       COALESCE(provider_ref, ''), failure_reason, refunded_amount, applied_at, created_at, updated_at`
+
+func scanPaymentIntent(row interface{ Scan(...any) error }) (*data.PaymentIntent, error) {
+	var intent data.PaymentIntent
+	var appliedAt sql.NullTime
+	err := row.Scan(
+		&intent.ID,
+		&intent.SubscriptionID,
+		&intent.UserID,
+		&intent.PlanID,
+		&intent.Purpose,
+		&intent.Amount,
+		&intent.DiscountApplied,
+		&intent.Currency,
+		&intent.Status,
+		&intent.ProviderRef,
+		&intent.FailureReason,
+		&intent.RefundedAmount,
+		&appliedAt,
+		&intent.CreatedAt,
+		&intent.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	intent.AppliedAt = appliedAt.Time
+	return &intent, nil
+}
+
+// insertPaymentIntent records a new intent to collect intent.Amount and fills
+// in the generated fields.
+func insertPaymentIntent(ctx context.Context, tx *sql.Tx, intent *data.PaymentIntent) error {
+	query := `
+INSERT INTO payment_intents (subscription_id, user_id, plan_id, purpose, amount, discount_applied, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING ` + paymentIntentColumns
+
+	args := []any{intent.SubscriptionID, intent.UserID, intent.PlanID, intent.Purpose, intent.Amount, intent.DiscountApplied, intent.Currency}
+	created, err := scanPaymentIntent(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return err
+	}
+	*intent = *created
+	return nil
+}
+
+// GetOpenPaymentIntent returns the subscription's latest intent of the given
+// purpose whose outcome has not been applied yet, or ErrPaymentIntentNotFound.
+func (s *Storage) GetOpenPaymentIntent(ctx context.Context, subId int64, purpose string) (*data.PaymentIntent, error) {
+	query := `
+SELECT ` + paymentIntentColumns + `
+FROM payment_intents
+WHERE subscription_id = $1 AND purpose = $2 AND applied_at IS NULL
+  AND status IN ('created', 'authorized', 'captured')
+ORDER BY id DESC
+LIMIT 1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	intent, err := scanPaymentIntent(s.db.QueryRowContext(ctx, query, subId, purpose))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetOpenPaymentIntent", ErrPaymentIntentNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetOpenPaymentIntent", err)
+		}
+	}
+	return intent, nil
+}
+
+// GetPaymentIntentByRef returns the intent the gateway knows by providerRef,
+// or ErrPaymentIntentNotFound.
+func (s *Storage) GetPaymentIntentByRef(ctx context.Context, providerRef string) (*data.PaymentIntent, error) {
+	query := `SELECT ` + paymentIntentColumns + ` FROM payment_intents WHERE provider_ref = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	intent, err := scanPaymentIntent(s.db.QueryRowContext(ctx, query, providerRef))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetPaymentIntentByRef", ErrPaymentIntentNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetPaymentIntentByRef", err)
+		}
+	}
+	return intent, nil
+}
+
+// TransitionPaymentIntent moves an intent from one status to another. It
+// fails with ErrInvalidPaymentTransition if the move is not allowed or the
+// intent is no longer in the from status, so concurrent updates cannot both
+// succeed. A non-empty providerRef is stored with the transition.
+func (s *Storage) TransitionPaymentIntent(ctx context.Context, id int64, from, to, providerRef, reason string) error {
+	if !data.CanTransitionPayment(from, to) {
+		return fmt.Errorf("%s:%w", "storage.postgres.TransitionPaymentIntent", ErrInvalidPaymentTransition)
+	}
+
+	query := `
+UPDATE payment_intents
+SET status = $3, provider_ref = COALESCE(NULLIF($4, ''), provider_ref), failure_reason = $5, updated_at = NOW()
+WHERE id = $1 AND status = $2
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, id, from, to, providerRef, reason)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.TransitionPaymentIntent", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.TransitionPaymentIntent", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", "storage.postgres.TransitionPaymentIntent", ErrInvalidPaymentTransition)
+	}
+	return nil
+}
+
+// RecordPaymentRefund adds amount to what has been refunded of a captured
+// intent, marking it refunded once nothing is left, and issues a credit note
+// against the invoice the intent paid.
+func (s *Storage) RecordPaymentRefund(ctx context.Context, id int64, amount int64) (*data.PaymentIntent, error) {
+	query := `
+UPDATE payment_intents
+SET refunded_amount = refunded_amount + $2,
+    status = CASE WHEN refunded_amount + $2 = amount THEN 'refunded' ELSE status END,
+    updated_at = NOW()
+WHERE id = $1 AND status = 'captured' AND refunded_amount + $2 <= amount
+RETURNING ` + paymentIntentColumns
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var intent *data.PaymentIntent
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		intent, err = scanPaymentIntent(tx.QueryRowContext(ctx, query, id, amount))
+		if err != nil {
+			return err
+		}
+		return issueCreditNote(ctx, tx, intent, amount)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.RecordPaymentRefund", ErrInvalidPaymentTransition)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.RecordPaymentRefund", err)
+		}
+	}
+	return intent, nil
+}
+
+// SyncPaymentRefund brings the refunded amount of an intent up to total, as
+// reported by the gateway. Totals lower than what is already recorded change
+// nothing, so refunds made through RecordPaymentRefund or ReserveRefund are
+// not counted twice. Any increase is issued as a credit note, and one that
+// refunds the payment for the current period in full ends the subscription's
+// access the same way ReserveRefund does.
+func (s *Storage) SyncPaymentRefund(ctx context.Context, id int64, total int64) (*data.PaymentIntent, error) {
+	query := `
+UPDATE payment_intents
+SET refunded_amount = GREATEST(refunded_amount, $2),
+    status = CASE WHEN GREATEST(refunded_amount, $2) = amount THEN 'refunded' ELSE status END,
+    updated_at = NOW()
+WHERE id = $1 AND status IN ('captured', 'refunded') AND $2 <= amount
+RETURNING ` + paymentIntentColumns
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var intent *data.PaymentIntent
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var previous int64
+		err := tx.QueryRowContext(ctx, `SELECT refunded_amount FROM payment_intents WHERE id = $1 FOR UPDATE`, id).Scan(&previous)
+		if err != nil {
+			return err
+		}
+		intent, err = scanPaymentIntent(tx.QueryRowContext(ctx, query, id, total))
+		if err != nil {
+			return err
+		}
+		if intent.RefundedAmount == previous {
+			return nil
+		}
+		if err := issueCreditNote(ctx, tx, intent, intent.RefundedAmount-previous); err != nil {
+			return err
+		}
+		if intent.Status != data.PaymentRefunded || intent.Purpose == data.PaymentProration {
+			return nil
+		}
+		return endRefundedPeriod(ctx, tx, intent, intent.RefundedAmount-previous)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.SyncPaymentRefund", ErrInvalidPaymentTransition)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.SyncPaymentRefund", err)
+		}
+	}
+	return intent, nil
+}
+
+// DisputePaymentIntent marks a captured intent disputed and restricts the
+// subscription it paid for, since the payment may be clawed back: a disputed
+// subscription can still be looked at but not debited, renewed or refunded,
+// and expires at the end of its period. A subscription that has already ended
+// is left as it is.
+func (s *Storage) DisputePaymentIntent(ctx context.Context, id int64, reason string) error {
+	query := `
+UPDATE payment_intents
+SET status = 'disputed', failure_reason = $2, updated_at = NOW()
+WHERE id = $1 AND status = 'captured'
+RETURNING subscription_id, amount
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var subId, amount int64
+		err := tx.QueryRowContext(ctx, query, id, reason).Scan(&subId, &amount)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidPaymentTransition
+			}
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, `
+UPDATE subscriptions
+SET status = 'disputed', grace_until = NULL, past_due_since = NULL
+WHERE id = $1 AND status NOT IN ('expired', 'failed', 'disputed')
+`, subId)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return nil
+		}
+		if err := cancelDunning(ctx, tx, subId); err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventPaymentDisputed, amount, reason)
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.DisputePaymentIntent", err)
+	}
+	return nil
+}
+
+// DebitWallet takes amount from the user's wallet if the balance covers it.
+// It reports false without error when the wallet is missing, too low or held
+// in another currency.
+func (s *Storage) DebitWallet(ctx context.Context, userId int64, amount int64, currency string) (bool, error) {
+	query := `
+UPDATE wallets
+SET balance = balance - $1, updated_at = NOW()
+WHERE user_id = $2 AND balance >= $1 AND currency = $3
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, amount, userId, currency)
+	if err != nil {
+		return false, fmt.Errorf("%s:%w", "storage.postgres.DebitWallet", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s:%w", "storage.postgres.DebitWallet", err)
+	}
+	return rowsAffected == 1, nil
+}
+
+// CaptureWalletPayment debits the amount of an authorized intent from its
+// user's wallet and marks the intent captured in one transaction, so the
+// wallet is never charged twice for it. It reports false without error, and
+// changes nothing, when the wallet does not cover the amount; an intent that
+// is no longer authorized fails with ErrInvalidPaymentTransition.
+func (s *Storage) CaptureWalletPayment(ctx context.Context, intentId int64) (bool, error) {
+	captureQuery := `
+UPDATE payment_intents
+SET status = 'captured', updated_at = NOW()
+WHERE id = $1 AND status = 'authorized'
+RETURNING user_id, amount, currency
+`
+	debitQuery := `
+UPDATE wallets
+SET balance = balance - $1, updated_at = NOW()
+WHERE user_id = $2 AND balance >= $1 AND currency = $3
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	errUncovered := errors.New("wallet does not cover the payment")
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var userId, amount int64
+		var currency string
+		err := tx.QueryRowContext(ctx, captureQuery, intentId).Scan(&userId, &amount, &currency)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidPaymentTransition
+			}
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, debitQuery, amount, userId, currency)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return errUncovered
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errUncovered) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%s:%w", "storage.postgres.CaptureWalletPayment", err)
+	}
+	return true, nil
+}
+
+// CreditWallet gives amount back to the user's wallet.
+func (s *Storage) CreditWallet(ctx context.Context, userId int64, amount int64, currency string) error {
+	query := `
+UPDATE wallets
+SET balance = balance + $1, updated_at = NOW()
+WHERE user_id = $2 AND currency = $3
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, amount, userId, currency)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.CreditWallet", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.CreditWallet", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", "storage.postgres.CreditWallet", ErrWalletNotFound)
+	}
+	return nil
+}
+
+// TopUpWallet adds amount to the user's wallet, opening it in currency on the
+// first top-up, and returns the new balance. A wallet held in another
+// currency is left alone and ErrWalletCurrency returned.
+func (s *Storage) TopUpWallet(ctx context.Context, userId int64, amount int64, currency string) (int64, error) {
+	query := `
+INSERT INTO wallets (user_id, balance, currency)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET balance = wallets.balance + EXCLUDED.balance, updated_at = NOW()
+WHERE wallets.currency = EXCLUDED.currency
+RETURNING balance
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var balance int64
+	err := s.db.QueryRowContext(ctx, query, userId, amount, currency).Scan(&balance)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, fmt.Errorf("%s:%w", "storage.postgres.TopUpWallet", ErrWalletCurrency)
+		default:
+			return 0, fmt.Errorf("%s:%w", "storage.postgres.TopUpWallet", err)
+		}
+	}
+	return balance, nil
+}
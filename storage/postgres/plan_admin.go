@@ -0,0 +1,404 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/money"
+	"time"
+)
+
+const planColumns = `id, name, description, rental_limit, price, duration_months, auto_renew, renewal_lead_hours,
+       grace_period_days, max_pause_days, trial_days, trial_rental_limit, low_limit_threshold,
+       expiry_reminder_days, family_id, version, grandfathered, visibility, available_from, available_until,
+       sort_order, refund_policy, archived_at, created_at`
+
+func scanPlan(row interface{ Scan(...any) error }) (*data.Plan, error) {
+	var plan data.Plan
+	var availableFrom, availableUntil, archivedAt, createdAt sql.NullTime
+	err := row.Scan(
+		&plan.ID,
+		&plan.Name,
+		&plan.Desc,
+		&plan.RentalLimit,
+		&plan.Price,
+		&plan.Duration,
+		&plan.AutoRenew,
+		&plan.RenewalLeadHours,
+		&plan.GracePeriodDays,
+		&plan.MaxPauseDays,
+		&plan.TrialDays,
+		&plan.TrialRentalLimit,
+		&plan.LowLimitThreshold,
+		&plan.ExpiryReminderDays,
+		&plan.FamilyID,
+		&plan.Version,
+		&plan.Grandfathered,
+		&plan.Visibility,
+		&availableFrom,
+		&availableUntil,
+		&plan.SortOrder,
+		&plan.RefundPolicy,
+		&archivedAt,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	plan.AvailableFrom = availableFrom.Time
+	plan.AvailableUntil = availableUntil.Time
+	plan.ArchivedAt = archivedAt.Time
+	plan.CreatedAt = createdAt.Time
+	return &plan, nil
+}
+
+// CreatePlan stores a new plan as the first version of its own family and
+// fills in the generated fields.
+func (s *Storage) CreatePlan(ctx context.Context, plan *data.Plan) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		created, err := insertPlanVersion(ctx, tx, plan, 0, 1)
+		if err != nil {
+			return err
+		}
+		*plan = *created
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.CreatePlan", err)
+	}
+	return nil
+}
+
+// UpdatePlan applies plan to the current plan with the same id. A plan nobody
+// is subscribed to is edited in place. Otherwise its subscribers would see the
+// change retroactively, so a new version is created in the same family and
+// the old one is archived. The returned plan is the one now on sale, and the
+// bool reports whether a new version was created. Visibility, availability
+// and sort order are not changed here; see SetPlanAvailability.
+func (s *Storage) UpdatePlan(ctx context.Context, plan *data.Plan) (*data.Plan, bool, error) {
+	inUseQuery := `
+SELECT EXISTS (
+    SELECT 1 FROM subscriptions
+    WHERE (plan_id = $1 OR pending_plan_id = $1) AND status NOT IN ('expired', 'failed')
+)
+`
+	updateQuery := `
+UPDATE subscription_plans
+SET name = $2, description = $3, rental_limit = $4, price = $5, duration_months = $6, auto_renew = $7,
+    renewal_lead_hours = $8, grace_period_days = $9, max_pause_days = $10, trial_days = $11,
+    trial_rental_limit = $12, low_limit_threshold = $13, expiry_reminder_days = $14
+WHERE id = $1
+RETURNING ` + planColumns
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var updated *data.Plan
+	var versioned bool
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		current, err := scanPlan(tx.QueryRowContext(ctx, `SELECT `+planColumns+` FROM subscription_plans WHERE id = $1 AND archived_at IS NULL FOR UPDATE`, plan.ID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrPlanNotFound
+			}
+			return err
+		}
+
+		var inUse bool
+		if err := tx.QueryRowContext(ctx, inUseQuery, plan.ID).Scan(&inUse); err != nil {
+			return err
+		}
+
+		if !inUse {
+			updated, err = scanPlan(tx.QueryRowContext(ctx, updateQuery, planArgs(plan.ID, plan)...))
+			if err != nil {
+				return err
+			}
+			return upsertPlanPrice(ctx, tx, updated.ID, money.Default, updated.Price)
+		}
+
+		next := *plan
+		next.Visibility = current.Visibility
+		next.AvailableFrom = current.AvailableFrom
+		next.AvailableUntil = current.AvailableUntil
+		next.SortOrder = current.SortOrder
+		next.RefundPolicy = current.RefundPolicy
+		updated, err = supersedePlan(ctx, tx, current, &next)
+		versioned = true
+		return err
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("%s:%w", "storage.postgres.UpdatePlan", err)
+	}
+	return updated, versioned, nil
+}
+
+// ArchivePlan takes a plan off sale. Existing subscribers keep it.
+func (s *Storage) ArchivePlan(ctx context.Context, planId int32) error {
+	query := `
+UPDATE subscription_plans
+SET archived_at = NOW()
+WHERE id = $1 AND archived_at IS NULL
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, planId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.ArchivePlan", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.ArchivePlan", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", "storage.postgres.ArchivePlan", ErrPlanNotFound)
+	}
+	return nil
+}
+
+// SetPlanPrice sets the price of a current plan in currency, in minor units.
+// Like UpdatePlan, it creates a new version instead if live subscribers pay
+// for the plan in that currency. Setting the default currency's price also
+// changes the plan's base price.
+func (s *Storage) SetPlanPrice(ctx context.Context, planId int32, currency string, amount int32) (*data.Plan, bool, error) {
+	inUseQuery := `
+SELECT EXISTS (
+    SELECT 1 FROM subscriptions
+    WHERE (plan_id = $1 OR pending_plan_id = $1) AND currency = $2 AND status NOT IN ('expired', 'failed')
+)
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var updated *data.Plan
+	var versioned bool
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		current, err := scanPlan(tx.QueryRowContext(ctx, `SELECT `+planColumns+` FROM subscription_plans WHERE id = $1 AND archived_at IS NULL FOR UPDATE`, planId))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrPlanNotFound
+			}
+			return err
+		}
+
+		var inUse bool
+		if err := tx.QueryRowContext(ctx, inUseQuery, planId, currency).Scan(&inUse); err != nil {
+			return err
+		}
+
+		updated = current
+		if inUse {
+			next := *current
+			if currency == money.Default {
+				next.Price = amount
+			}
+			updated, err = supersedePlan(ctx, tx, current, &next)
+			if err != nil {
+				return err
+			}
+			versioned = true
+		}
+		if err := upsertPlanPrice(ctx, tx, updated.ID, currency, amount); err != nil {
+			return err
+		}
+		if currency == money.Default {
+			updated.Price = amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("%s:%w", "storage.postgres.SetPlanPrice", err)
+	}
+	return updated, versioned, nil
+}
+
+// SetPlanAvailability changes how a current plan is listed and when it can be
+// bought. None of this affects existing subscribers, so the plan is always
+// edited in place.
+func (s *Storage) SetPlanAvailability(ctx context.Context, plan *data.Plan) (*data.Plan, error) {
+	query := `
+UPDATE subscription_plans
+SET visibility = $2, available_from = $3, available_until = $4, sort_order = $5
+WHERE id = $1 AND archived_at IS NULL
+RETURNING ` + planColumns
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	args := []any{plan.ID, plan.Visibility, nullTime(plan.AvailableFrom), nullTime(plan.AvailableUntil), plan.SortOrder}
+	updated, err := scanPlan(s.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.SetPlanAvailability", ErrPlanNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.SetPlanAvailability", err)
+		}
+	}
+	return updated, nil
+}
+
+// NextPlanAvailabilityChange returns the first time after now at which a
+// current public plan goes on or off sale, or the zero time if none is
+// scheduled.
+func (s *Storage) NextPlanAvailabilityChange(ctx context.Context, now time.Time) (time.Time, error) {
+	query := `
+SELECT MIN(t) FROM (
+    SELECT available_from AS t FROM subscription_plans
+    WHERE archived_at IS NULL AND visibility = 'public' AND available_from > $1
+    UNION ALL
+    SELECT available_until FROM subscription_plans
+    WHERE archived_at IS NULL AND visibility = 'public' AND available_until > $1
+) boundaries
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var next sql.NullTime
+	if err := s.db.QueryRowContext(ctx, query, now).Scan(&next); err != nil {
+		return time.Time{}, fmt.Errorf("%s:%w", "storage.postgres.NextPlanAvailabilityChange", err)
+	}
+	return next.Time, nil
+}
+
+// SetPlanRefundPolicy sets how subscribers of a plan version are refunded.
+// Like grandfathering it is a term of sale rather than part of the plan, so
+// it is changed in place.
+func (s *Storage) SetPlanRefundPolicy(ctx context.Context, planId int32, policy string) error {
+	query := `
+UPDATE subscription_plans
+SET refund_policy = $2
+WHERE id = $1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, planId, policy)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.SetPlanRefundPolicy", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.SetPlanRefundPolicy", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", "storage.postgres.SetPlanRefundPolicy", ErrPlanNotFound)
+	}
+	return nil
+}
+
+// SetPlanGrandfathered sets whether subscribers of a plan version keep it once
+// a newer version exists. It works on archived versions too, so a superseded
+// price can be phased out after the fact.
+func (s *Storage) SetPlanGrandfathered(ctx context.Context, planId int32, grandfathered bool) error {
+	query := `
+UPDATE subscription_plans
+SET grandfathered = $2
+WHERE id = $1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, planId, grandfathered)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.SetPlanGrandfathered", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.SetPlanGrandfathered", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", "storage.postgres.SetPlanGrandfathered", ErrPlanNotFound)
+	}
+	return nil
+}
+
+// supersedePlan archives current and inserts next as the following version of
+// its family, carrying over the prices in other currencies.
+func supersedePlan(ctx context.Context, tx *sql.Tx, current, next *data.Plan) (*data.Plan, error) {
+	_, err := tx.ExecContext(ctx, `UPDATE subscription_plans SET archived_at = NOW() WHERE id = $1`, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := insertPlanVersion(ctx, tx, next, current.FamilyID, current.Version+1)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO plan_prices (plan_id, currency, amount_minor)
+SELECT $2, currency, amount_minor FROM plan_prices WHERE plan_id = $1
+ON CONFLICT (plan_id, currency) DO NOTHING
+`, current.ID, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// upsertPlanPrice sets the price of planId in currency. The default currency's
+// price is kept in step with the plan's own price column.
+func upsertPlanPrice(ctx context.Context, tx *sql.Tx, planId int32, currency string, amount int32) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO plan_prices (plan_id, currency, amount_minor)
+VALUES ($1, $2, $3)
+ON CONFLICT (plan_id, currency) DO UPDATE SET amount_minor = EXCLUDED.amount_minor
+`, planId, currency, amount)
+	if err != nil || currency != money.Default {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE subscription_plans SET price = $2 WHERE id = $1`, planId, amount)
+	return err
+}
+
+// insertPlanVersion inserts plan as version of familyId. A zero familyId
+// starts a new family with the inserted plan as its root. Its price is stored
+// as the default currency price.
+func insertPlanVersion(ctx context.Context, tx *sql.Tx, plan *data.Plan, familyId, version int32) (*data.Plan, error) {
+	query := `
+INSERT INTO subscription_plans (family_id, name, description, rental_limit, price, duration_months, auto_renew,
+    renewal_lead_hours, grace_period_days, max_pause_days, trial_days, trial_rental_limit, low_limit_threshold,
+    expiry_reminder_days, id, version, visibility, available_from, available_until, sort_order, refund_policy)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+RETURNING ` + planColumns
+
+	var id int32
+	err := tx.QueryRowContext(ctx, `SELECT nextval(pg_get_serial_sequence('subscription_plans', 'id'))`).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	if familyId == 0 {
+		familyId = id
+	}
+
+	args := append(planArgs(familyId, plan), id, version, plan.Visibility, nullTime(plan.AvailableFrom), nullTime(plan.AvailableUntil), plan.SortOrder, plan.RefundPolicy)
+	created, err := scanPlan(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, err
+	}
+	return created, upsertPlanPrice(ctx, tx, created.ID, money.Default, created.Price)
+}
+
+func planArgs(first int32, plan *data.Plan) []any {
+	return []any{
+		first,
+		plan.Name,
+		plan.Desc,
+		plan.RentalLimit,
+		plan.Price,
+		plan.Duration,
+		plan.AutoRenew,
+		plan.RenewalLeadHours,
+		plan.GracePeriodDays,
+		plan.MaxPauseDays,
+		plan.TrialDays,
+		plan.TrialRentalLimit,
+		plan.LowLimitThreshold,
+		plan.ExpiryReminderDays,
+	}
+}
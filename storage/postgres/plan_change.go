@@ -0,0 +1,345 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// GetSubscription returns the user's subscription row regardless of status.
+func (s *Storage) GetSubscription(ctx context.Context, userId int64) (*data.Subscription, error) {
+	query := `
+SELECT id, user_id, COALESCE(plan_id, 0), remaining_limit, expires_at, status, period_started_at,
+       COALESCE(pending_plan_id, 0), pending_change_at, outstanding_amount, currency, bucket_provisioned,
+       trial_ends_at, created_at
+FROM subscriptions
+WHERE user_id = $1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var sub data.Subscription
+	var pendingChangeAt, trialEndsAt sql.NullTime
+
+	err := s.db.QueryRowContext(ctx, query, userId).Scan(
+		&sub.ID,
+		&sub.UserID,
+		&sub.PlanID,
+		&sub.RemainingLimit,
+		&sub.ExpiresAt,
+		&sub.Status,
+		&sub.PeriodStartedAt,
+		&sub.PendingPlanID,
+		&pendingChangeAt,
+		&sub.OutstandingAmount,
+		&sub.Currency,
+		&sub.BucketProvisioned,
+		&trialEndsAt,
+		&sub.CreatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetSubscription", ErrSubNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetSubscription", err)
+		}
+	}
+	sub.PendingChangeAt = pendingChangeAt.Time
+	sub.TrialEndsAt = trialEndsAt.Time
+
+	return &sub, nil
+}
+
+// GetPlanPrice returns the price of a plan in minor units of currency, or
+// ErrPlanNotFound if the plan is not sold in it.
+func (s *Storage) GetPlanPrice(ctx context.Context, planId int32, currency string) (int32, error) {
+	query := `
+SELECT amount_minor FROM plan_prices
+WHERE plan_id = $1 AND currency = $2
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var amount int32
+	err := s.db.QueryRowContext(ctx, query, planId, currency).Scan(&amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, fmt.Errorf("%s:%w", "storage.postgres.GetPlanPrice", ErrPlanNotFound)
+		default:
+			return 0, fmt.Errorf("%s:%w", "storage.postgres.GetPlanPrice", err)
+		}
+	}
+	return amount, nil
+}
+
+// GetPlan returns a single plan by id, archived or not.
+func (s *Storage) GetPlan(ctx context.Context, planId int32) (*data.Plan, error) {
+	query := `SELECT ` + planColumns + ` FROM subscription_plans WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	plan, err := scanPlan(s.db.QueryRowContext(ctx, query, planId))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetPlan", ErrPlanNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetPlan", err)
+		}
+	}
+	return plan, nil
+}
+
+// CreateProrationIntent records an intent to collect the prorated amount of
+// an immediate plan change, or returns ErrSubNotFound if the user's
+// subscription is no longer on currentPlanId.
+func (s *Storage) CreateProrationIntent(ctx context.Context, userId int64, currentPlanId int32, change data.PlanChange) (*data.PaymentIntent, error) {
+	query := `
+SELECT id, currency FROM subscriptions
+WHERE user_id = $1 AND plan_id = $2 AND status IN ('active', 'grace')
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	intent := data.PaymentIntent{
+		UserID:  userId,
+		PlanID:  change.PlanID,
+		Purpose: data.PaymentProration,
+		Amount:  change.Amount,
+	}
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, userId, currentPlanId).Scan(&intent.SubscriptionID, &intent.Currency)
+		if err != nil {
+			return err
+		}
+		return insertPaymentIntent(ctx, tx, &intent)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.CreateProrationIntent", ErrSubNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.CreateProrationIntent", err)
+		}
+	}
+	return &intent, nil
+}
+
+// ChangeSubsPlan applies a quoted plan change to the user's subscription.
+// Immediate changes switch the plan now and top up the rental limit; other
+// changes are recorded as pending and applied when the period rolls over. An
+// immediate change with an amount to pay is only applied against intentId,
+// its captured proration intent, which is marked applied and invoiced. The
+// change only applies if the subscription is still on currentPlanId, so a
+// quote computed from stale data is rejected with ErrSubNotFound. A non-empty
+// couponCode is redeemed against the new plan in the same transaction; if it
+// cannot be, one of the coupon errors is returned.
+func (s *Storage) ChangeSubsPlan(ctx context.Context, userId int64, currentPlanId int32, change data.PlanChange, couponCode string, intentId int64) error {
+	immediateQuery := `
+UPDATE subscriptions
+SET plan_id = $1,
+    remaining_limit = remaining_limit + $2,
+    pending_plan_id = NULL,
+    pending_change_at = NULL
+WHERE user_id = $3 AND plan_id = $4 AND status IN ('active', 'grace')
+RETURNING id, remaining_limit
+`
+	applyIntentQuery := `
+UPDATE payment_intents
+SET applied_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND subscription_id = $2 AND purpose = 'proration' AND amount = $3
+  AND status = 'captured' AND applied_at IS NULL
+RETURNING ` + paymentIntentColumns
+
+	scheduledQuery := `
+UPDATE subscriptions
+SET pending_plan_id = $1, pending_change_at = $2
+WHERE user_id = $3 AND plan_id = $4 AND status IN ('active', 'grace')
+RETURNING id
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var subId int64
+
+		if !change.Immediate {
+			err := tx.QueryRowContext(ctx, scheduledQuery, change.PlanID, change.EffectiveAt, userId, currentPlanId).Scan(&subId)
+			if err != nil {
+				return err
+			}
+			err = insertSubEvent(ctx, tx, subId, data.EventPlanChangeScheduled, 0, change.Kind)
+			if err != nil {
+				return err
+			}
+		} else {
+			var remainingLimit int64
+			args := []any{change.PlanID, change.LimitTopUp, userId, currentPlanId}
+			err := tx.QueryRowContext(ctx, immediateQuery, args...).Scan(&subId, &remainingLimit)
+			if err != nil {
+				return err
+			}
+			_, err = insertLedgerEntry(ctx, tx, subId, int64(change.LimitTopUp), data.AccountPlanAllowance, data.EventPlanChanged, "", remainingLimit)
+			if err != nil {
+				return err
+			}
+			err = insertSubEvent(ctx, tx, subId, data.EventPlanChanged, change.Amount, change.Kind)
+			if err != nil {
+				return err
+			}
+			if change.Amount > 0 {
+				intent, err := scanPaymentIntent(tx.QueryRowContext(ctx, applyIntentQuery, intentId, subId, change.Amount))
+				if err != nil {
+					return err
+				}
+				if err := issueProrationInvoice(ctx, tx, intent); err != nil {
+					return err
+				}
+			}
+		}
+
+		if couponCode == "" {
+			return nil
+		}
+		var price int64
+		var currency string
+		err := tx.QueryRowContext(ctx, `
+SELECT pp.amount_minor, s.currency
+FROM subscriptions s
+JOIN plan_prices pp ON pp.currency = s.currency
+WHERE s.id = $1 AND pp.plan_id = $2
+`, subId, change.PlanID).Scan(&price, &currency)
+		if err != nil {
+			return err
+		}
+		return redeemCoupon(ctx, tx, couponCode, userId, subId, change.PlanID, price, currency)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%s:%w", "storage.postgres.ChangeSubsPlan", ErrSubNotFound)
+		default:
+			return fmt.Errorf("%s:%w", "storage.postgres.ChangeSubsPlan", err)
+		}
+	}
+	return nil
+}
+
+// CancelPendingPlanChange drops the user's scheduled plan change, or returns
+// ErrSubNotFound if nothing is scheduled.
+func (s *Storage) CancelPendingPlanChange(ctx context.Context, userId int64) error {
+	query := `
+UPDATE subscriptions
+SET pending_plan_id = NULL, pending_change_at = NULL
+WHERE user_id = $1 AND pending_plan_id IS NOT NULL
+RETURNING id
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var subId int64
+		err := tx.QueryRowContext(ctx, query, userId).Scan(&subId)
+		if err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventPlanChangeCancelled, 0, "")
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%s:%w", "storage.postgres.CancelPendingPlanChange", ErrSubNotFound)
+		default:
+			return fmt.Errorf("%s:%w", "storage.postgres.CancelPendingPlanChange", err)
+		}
+	}
+	return nil
+}
+
+// ApplyDuePlanChanges switches up to limit subscriptions whose scheduled plan
+// change is due onto the pending plan. The remaining limit is capped at the
+// new plan's rental limit; nothing is charged, since paid rollovers are done
+// by the renewal engine, which applies pending plans itself.
+func (s *Storage) ApplyDuePlanChanges(ctx context.Context, now time.Time, limit int) ([]data.Subscription, error) {
+	dueQuery := `
+SELECT s.id, s.remaining_limit, np.rental_limit
+FROM subscriptions s
+JOIN subscription_plans np ON np.id = s.pending_plan_id
+WHERE s.pending_change_at <= $1 AND s.status IN ('active', 'grace')
+ORDER BY s.pending_change_at
+LIMIT $2
+FOR UPDATE OF s SKIP LOCKED
+`
+	applyQuery := `
+UPDATE subscriptions
+SET plan_id = pending_plan_id,
+    remaining_limit = LEAST(remaining_limit, $2),
+    pending_plan_id = NULL,
+    pending_change_at = NULL
+WHERE id = $1
+RETURNING id, user_id, plan_id, remaining_limit, expires_at, status
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	type dueChange struct {
+		subId         int64
+		previousLimit int64
+		rentalLimit   int32
+	}
+
+	var applied []data.Subscription
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, dueQuery, now, limit)
+		if err != nil {
+			return err
+		}
+		due := []dueChange{}
+		for rows.Next() {
+			var d dueChange
+			if err := rows.Scan(&d.subId, &d.previousLimit, &d.rentalLimit); err != nil {
+				rows.Close()
+				return err
+			}
+			due = append(due, d)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		for _, d := range due {
+			var sub data.Subscription
+			err := tx.QueryRowContext(ctx, applyQuery, d.subId, d.rentalLimit).Scan(
+				&sub.ID,
+				&sub.UserID,
+				&sub.PlanID,
+				&sub.RemainingLimit,
+				&sub.ExpiresAt,
+				&sub.Status,
+			)
+			if err != nil {
+				return err
+			}
+			_, err = insertLedgerEntry(ctx, tx, sub.ID, int64(sub.RemainingLimit)-d.previousLimit, data.AccountPlanAllowance, data.EventPlanChanged, "", int64(sub.RemainingLimit))
+			if err != nil {
+				return err
+			}
+			if err := insertSubEvent(ctx, tx, sub.ID, data.EventPlanChanged, 0, "scheduled"); err != nil {
+				return err
+			}
+			applied = append(applied, sub)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ApplyDuePlanChanges", err)
+	}
+	return applied, nil
+}
@@ -8,6 +8,7 @@ import (
 	_ "github.com/lib/pq"
 	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
 	"log"
+	"subscriptionMService/internal/data"
 	"time"
 )
 
@@ -70,53 +71,217 @@ func (s *Storage) Close() error {
 	return s.db.Close()
 }
 
-func (s *Storage) Subscribe(ctx context.Context, userID int64, planID int32) (int64, subs.Status) {
+// Subscribe starts a subscription for the user on planID, priced in currency.
+// A cancelled or expired subscription is reactivated instead: a cancellation
+// still inside its paid period is undone and keeps its currency, otherwise a
+// new period starts, as a trial if the plan has one and the user never had it.
+// A new paid period records a payment intent for the price less the discount
+// of couponCode. A subscription with a payment to collect or no bucket yet is
+// left pending, as the returned bool reports, until the caller calls
+// ActivateSubscription or FailPendingSubscription. An unusable coupon or a
+// plan not sold in currency fails with STATUS_INVALID_PLAN.
+func (s *Storage) Subscribe(ctx context.Context, userID int64, planID int32, couponCode, currency string) (int64, bool, subs.Status) {
+	planQuery := `
+SELECT p.rental_limit, p.duration_months, p.trial_days, p.trial_rental_limit, pp.amount_minor
+FROM subscription_plans p
+JOIN plan_prices pp ON pp.plan_id = p.id AND pp.currency = $2
+WHERE id = $1 AND archived_at IS NULL
+  AND (available_from IS NULL OR available_from <= NOW())
+  AND (available_until IS NULL OR available_until > NOW())
+`
 	query := `
-INSERT INTO subscriptions (user_id, plan_id, remaining_limit, expires_at)
-VALUES ($1, $2, $3, $4)
-RETURNING id`
-
-	limit, durationMonths, err := s.getDetailsFromPlan(planID)
-	expiresAt := addMonths(time.Now(), durationMonths)
-
-	if err != nil {
-		println(err.Error())
-		return 0, subs.Status_STATUS_INTERNAL_ERROR
-	}
+INSERT INTO subscriptions (user_id, plan_id, remaining_limit, expires_at, status, trial_ends_at, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (user_id) DO UPDATE
+SET plan_id = EXCLUDED.plan_id,
+    remaining_limit = CASE WHEN subscriptions.status = 'cancelled' AND subscriptions.expires_at > NOW()
+        THEN subscriptions.remaining_limit ELSE EXCLUDED.remaining_limit END,
+    expires_at = CASE WHEN subscriptions.status = 'cancelled' AND subscriptions.expires_at > NOW()
+        THEN subscriptions.expires_at ELSE EXCLUDED.expires_at END,
+    status = CASE WHEN subscriptions.status = 'cancelled' AND subscriptions.expires_at > NOW()
+        THEN CASE WHEN subscriptions.trial_ends_at > NOW() THEN 'trialing' ELSE 'active' END
+        ELSE EXCLUDED.status END,
+    trial_ends_at = CASE WHEN subscriptions.status = 'cancelled' AND subscriptions.expires_at > NOW()
+        THEN subscriptions.trial_ends_at ELSE EXCLUDED.trial_ends_at END,
+    currency = CASE WHEN subscriptions.status = 'cancelled' AND subscriptions.expires_at > NOW()
+        THEN subscriptions.currency ELSE EXCLUDED.currency END,
+    cancelled_at = NULL,
+    cancel_reason = NULL,
+    grace_until = NULL,
+    renewal_attempted_at = NULL,
+    period_started_at = CASE WHEN subscriptions.status = 'cancelled' AND subscriptions.expires_at > NOW()
+        THEN subscriptions.period_started_at ELSE NOW() END,
+    pending_plan_id = NULL,
+    pending_change_at = NULL
+WHERE subscriptions.status IN ('cancelled', 'expired', 'failed')
+RETURNING id, (xmax = 0) AS inserted, remaining_limit, status, currency
+`
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
 	var subId int64
-	args := []any{userID, planID, limit, expiresAt}
-	status := s.db.QueryRowContext(ctx, query, args...).Scan(&subId)
+	var inserted bool
+	var newStatus string
+	status := s.withTx(ctx, func(tx *sql.Tx) error {
+		var limit, trialLimit, trialDays int32
+		var price int64
+		var durationMonths subs.Duration
+		err := tx.QueryRowContext(ctx, planQuery, planID, currency).Scan(&limit, &durationMonths, &trialDays, &trialLimit, &price)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrPlanNotFound
+			}
+			return err
+		}
+
+		var previousLimit int64
+		var previousStatus string
+		var previousExpiresAt time.Time
+		var bucketProvisioned bool
+		err = tx.QueryRowContext(ctx, `SELECT remaining_limit, status, expires_at, bucket_provisioned FROM subscriptions WHERE user_id = $1 FOR UPDATE`, userID).Scan(&previousLimit, &previousStatus, &previousExpiresAt, &bucketProvisioned)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		resuming := previousStatus == "cancelled" && time.Now().Before(previousExpiresAt)
+
+		newStatus = "active"
+		expiresAt := addMonths(time.Now(), durationMonths)
+		var trialEndsAt *time.Time
+		if trialDays > 0 && !resuming {
+			trial, err := redeemTrial(ctx, tx, userID, planID)
+			if err != nil {
+				return err
+			}
+			if trial {
+				newStatus = "trialing"
+				expiresAt = time.Now().AddDate(0, 0, int(trialDays))
+				trialEndsAt = &expiresAt
+				if trialLimit > 0 {
+					limit = trialLimit
+				}
+			}
+		}
+
+		chargeNow := !resuming && newStatus != "trialing"
+		if chargeNow || !bucketProvisioned {
+			newStatus = "pending"
+		}
+
+		var remainingLimit int64
+		var subCurrency string
+		args := []any{userID, planID, limit, expiresAt, newStatus, trialEndsAt, currency}
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&subId, &inserted, &remainingLimit, &newStatus, &subCurrency)
+		if err != nil {
+			return err
+		}
+		eventType := data.EventReactivated
+		if inserted {
+			eventType = data.EventSubscribed
+		}
+		details := ""
+		if trialEndsAt != nil || newStatus == "trialing" {
+			details = "trial"
+		}
+		_, err = insertLedgerEntry(ctx, tx, subId, remainingLimit-previousLimit, data.AccountPlanAllowance, eventType, "", remainingLimit)
+		if err != nil {
+			return err
+		}
+		err = insertSubEvent(ctx, tx, subId, eventType, 0, details)
+		if err != nil {
+			return err
+		}
+		if couponCode != "" {
+			err = redeemCoupon(ctx, tx, couponCode, userID, subId, planID, price, subCurrency)
+			if err != nil {
+				return err
+			}
+		}
+		if !chargeNow {
+			return nil
+		}
+
+		var discount int64
+		err = tx.QueryRowContext(ctx, `SELECT discount_amount FROM subscriptions WHERE id = $1`, subId).Scan(&discount)
+		if err != nil {
+			return err
+		}
+		intent := data.PaymentIntent{
+			SubscriptionID:  subId,
+			UserID:          userID,
+			PlanID:          planID,
+			Purpose:         data.PaymentSubscribe,
+			Amount:          price - min(discount, price),
+			DiscountApplied: min(discount, price),
+			Currency:        subCurrency,
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE subscriptions SET discount_amount = discount_amount - $1 WHERE id = $2`, intent.DiscountApplied, subId)
+		if err != nil {
+			return err
+		}
+		return insertPaymentIntent(ctx, tx, &intent)
+	})
 	if status != nil {
 		switch {
 		case errors.Is(status, sql.ErrNoRows):
-			return 0, subs.Status_STATUS_NOT_SUBSCRIBED // TODO: change it to subID not found
+			return 0, false, subs.Status_STATUS_ALREADY_SUBSCRIBED
+		case errors.Is(status, ErrPlanNotFound), IsCouponError(status):
+			return 0, false, subs.Status_STATUS_INVALID_PLAN
 		default:
 			println(status.Error())
-			return 0, subs.Status_STATUS_INTERNAL_ERROR
+			return 0, false, subs.Status_STATUS_INTERNAL_ERROR
 		}
 
 	}
 	println("db part")
 
-	return subId, subs.Status_STATUS_OK
+	return subId, newStatus == "pending", subs.Status_STATUS_OK
+
+}
 
+// redeemTrial records that the user has used their one free trial. It reports
+// false if they already had one.
+func redeemTrial(ctx context.Context, tx *sql.Tx, userID int64, planID int32) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+INSERT INTO trial_redemptions (user_id, plan_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO NOTHING
+`, userID, planID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
 }
 
-func (s *Storage) ExtractFromBalance(ctx context.Context, value int64, userId int64) (subs.Status, string, int64) {
+// ExtractFromBalance debits value from the user's remaining limit and records
+// the movement in the ledger with the caller's reason and reference. Past due
+// subscriptions cannot be debited until their payment goes through, nor
+// disputed ones at all.
+func (s *Storage) ExtractFromBalance(ctx context.Context, value int64, userId int64, reason, reference string) (subs.Status, string, int64) {
 	query := `UPDATE subscriptions
 SET remaining_limit = remaining_limit - $1
-WHERE user_id = $2 AND remaining_limit >= $1
-RETURNING remaining_limit
+WHERE user_id = $2 AND remaining_limit >= $1 AND status NOT IN ('paused', 'pending', 'failed', 'past_due', 'disputed')
+RETURNING id, remaining_limit
 `
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	var remaining_limit int64
+	var subId, remaining_limit int64
 
-	err := s.db.QueryRowContext(ctx, query, value, userId).Scan(&remaining_limit)
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, value, userId).Scan(&subId, &remaining_limit)
+		if err != nil {
+			return err
+		}
+		_, err = insertLedgerEntry(ctx, tx, subId, -value, data.AccountRentals, reason, reference, remaining_limit)
+		if err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventBalanceDebited, -value, reference)
+	})
 	if err != nil {
 		switch err {
 		case sql.ErrNoRows:
@@ -128,64 +293,68 @@ RETURNING remaining_limit
 	return subs.Status_STATUS_OK, "extracting from balance was successful!", remaining_limit
 }
 
-func (s *Storage) AddToBalance(ctx context.Context, value int64, userId int64) (subs.Status, string, int64) {
+// AddToBalance credits value to the user's remaining limit and records the
+// movement in the ledger with the caller's reason and reference.
+func (s *Storage) AddToBalance(ctx context.Context, value int64, userId int64, reason, reference string) (subs.Status, string, int64) {
 	query := `UPDATE subscriptions
 SET remaining_limit = remaining_limit + $1
 WHERE user_id = $2 
-RETURNING remaining_limit
+RETURNING id, remaining_limit
 `
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	var remaining_limit int64
+	var subId, remaining_limit int64
 
-	err := s.db.QueryRowContext(ctx, query, value, userId).Scan(&remaining_limit)
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, value, userId).Scan(&subId, &remaining_limit)
+		if err != nil {
+			return err
+		}
+		_, err = insertLedgerEntry(ctx, tx, subId, value, data.AccountRentals, reason, reference, remaining_limit)
+		if err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventBalanceCredited, value, reference)
+	})
 	if err != nil {
 		return subs.Status_STATUS_INTERNAL_ERROR, "internal error!", 0
 	}
 	return subs.Status_STATUS_OK, "adding to balance was successful!", remaining_limit
 }
 
-func (s *Storage) Unsubscribe(ctx context.Context, userID int64) subs.Status {
-	query := `
-DELETE FROM subscriptions
-WHERE user_id = $1
-`
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	results, err := s.db.ExecContext(ctx, query, userID)
-	if err != nil {
-		return subs.Status_STATUS_INTERNAL_ERROR
-	}
-	rowsAffected, err := results.RowsAffected()
-	if err != nil {
-		return subs.Status_STATUS_INTERNAL_ERROR
-	}
-
-	if rowsAffected == 0 {
-		return subs.Status_STATUS_NOT_SUBSCRIBED
-	}
-	return subs.Status_STATUS_OK
-}
-
-func (s *Storage) ChangeSubsPlan(ctx context.Context, userId int64, newPlanId int32) subs.Status {
+// Unsubscribe cancels the user's subscription at the end of the paid period.
+// The row is kept for history; access lasts until expires_at, after which the
+// expiry worker moves it to expired. Cancelling a past due subscription
+// stops its payment retries.
+func (s *Storage) Unsubscribe(ctx context.Context, userID int64, reason string) subs.Status {
 	query := `
 UPDATE subscriptions
-SET plan_id = $1
-WHERE user_id = $2
+SET status = 'cancelled', cancelled_at = NOW(), cancel_reason = NULLIF($2, ''), past_due_since = NULL
+WHERE user_id = $1 AND status IN ('active', 'grace', 'trialing', 'past_due')
 RETURNING id
 `
-	args := []any{newPlanId, userId}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	var subId int64
-	err := s.db.QueryRowContext(ctx, query, args...).Scan(&subId)
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var subId int64
+		err := tx.QueryRowContext(ctx, query, userID, reason).Scan(&subId)
+		if err != nil {
+			return err
+		}
+		err = declineRetentionOffers(ctx, tx, subId)
+		if err != nil {
+			return err
+		}
+		if err := cancelDunning(ctx, tx, subId); err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventCancelled, 0, reason)
+	})
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return subs.Status_STATUS_INVALID_USER // TODO: мб поменять
+			return subs.Status_STATUS_NOT_SUBSCRIBED
 		default:
 			return subs.Status_STATUS_INTERNAL_ERROR
 		}
@@ -197,6 +366,8 @@ func (s *Storage) GetSubDetails(ctx context.Context, userId int64) (int32, strin
 	query := `
 SELECT plan_id, remaining_limit, expires_at FROM subscriptions
 WHERE user_id = $1
+  AND (status IN ('active', 'grace', 'trialing', 'paused', 'past_due', 'disputed')
+    OR (status = 'cancelled' AND expires_at > NOW()))
 `
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -223,15 +394,16 @@ WHERE user_id = $1
 
 func (s *Storage) CheckSubscription(ctx context.Context, userId int64) subs.Status {
 	query := `
-SELECT status FROM subscriptions
+SELECT status, expires_at FROM subscriptions
 WHERE user_id = $1
 `
 	println(userId)
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	var subStatus string
+	var expiresAt time.Time
 
-	err := s.db.QueryRowContext(ctx, query, userId).Scan(&subStatus)
+	err := s.db.QueryRowContext(ctx, query, userId).Scan(&subStatus, &expiresAt)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -241,18 +413,26 @@ WHERE user_id = $1
 		}
 	}
 
-	return stringToSubsStatus(subStatus)
+	return stringToSubsStatus(subStatus, expiresAt)
 
 }
 
-func (s *Storage) ListPlans(ctx context.Context) []*subs.Plan {
+// ListPlans returns the public plans on sale now that have a price in
+// currency, priced in its minor units.
+func (s *Storage) ListPlans(ctx context.Context, currency string) []*subs.Plan {
 	query := `
-SELECT id, name, description, rental_limit, price, duration_months FROM subscription_plans 
+SELECT p.id, p.name, p.description, p.rental_limit, pp.amount_minor, p.duration_months
+FROM subscription_plans p
+JOIN plan_prices pp ON pp.plan_id = p.id AND pp.currency = $1
+WHERE p.archived_at IS NULL AND p.visibility = 'public'
+  AND (p.available_from IS NULL OR p.available_from <= NOW())
+  AND (p.available_until IS NULL OR p.available_until > NOW())
+ORDER BY p.sort_order, p.id
 `
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	rows, err := s.db.QueryContext(ctx, query)
+	rows, err := s.db.QueryContext(ctx, query, currency)
 	if err != nil {
 		return nil
 	}
@@ -287,30 +467,6 @@ SELECT id, name, description, rental_limit, price, duration_months FROM subscrip
 
 }
 
-func (s *Storage) getDetailsFromPlan(planId int32) (int32, subs.Duration, error) {
-	query := `
-SELECT rental_limit, duration_months FROM subscription_plans
-WHERE id = $1
-`
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-	var rentalLimit int32
-	var durationsMonths subs.Duration
-
-	err := s.db.QueryRowContext(ctx, query, planId).Scan(&rentalLimit, &durationsMonths)
-	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			return 0, subs.Duration(0), fmt.Errorf("%s:%d", "storage.postgres.getLimitFromId", ErrPlanNotFound)
-		default:
-			return 0, subs.Duration(0), fmt.Errorf("%s:%d", "storage.postgres.getLimitFromId", err)
-		}
-	}
-
-	return rentalLimit, durationsMonths, nil
-
-}
-
 func (s *Storage) getNameFromPlan(planId int32) (string, error) {
 	query := `
 SELECT name FROM subscription_plans
@@ -334,10 +490,20 @@ WHERE id = $1
 
 }
 
-func stringToSubsStatus(status string) subs.Status {
-	if status == "active" {
+// stringToSubsStatus reports whether a subscription in the given state still
+// grants access. Cancelled subscriptions keep access until the paid period ends.
+// Past due subscriptions stay subscribed while payment is retried, though
+// ExtractFromBalance refuses them.
+func stringToSubsStatus(status string, expiresAt time.Time) subs.Status {
+	switch status {
+	case "active", "grace", "trialing", "past_due":
 		return subs.Status_STATUS_SUBSCRIBED
-	} else {
+	case "cancelled":
+		if time.Now().Before(expiresAt) {
+			return subs.Status_STATUS_SUBSCRIBED
+		}
+		return subs.Status_STATUS_NOT_SUBSCRIBED
+	default:
 		return subs.Status_STATUS_NOT_SUBSCRIBED
 	}
 }
@@ -350,7 +516,8 @@ func addMonths(t time.Time, months subs.Duration) time.Time {
 	month += time.Month(months)
 	newTime := time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 
-	for newTime.Month() != month%12 {
+	wantMonth := (month-1)%12 + 1
+	for newTime.Month() != wantMonth {
 		day--
 		newTime = time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 	}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// ActivateSubscription finishes a pending subscription once its bucket has
+// been provisioned, moving it to trialing or active. A captured subscribe
+// payment is applied and invoiced.
+func (s *Storage) ActivateSubscription(ctx context.Context, subId int64) error {
+	query := `
+UPDATE subscriptions
+SET status = CASE WHEN trial_ends_at > NOW() THEN 'trialing' ELSE 'active' END, bucket_provisioned = TRUE
+WHERE id = $1 AND status = 'pending'
+  AND NOT EXISTS (
+    SELECT 1 FROM payment_intents
+    WHERE subscription_id = $1 AND purpose = 'subscribe' AND applied_at IS NULL AND status IN ('created', 'authorized')
+  )
+RETURNING id
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, subId).Scan(&subId)
+		if err != nil {
+			return err
+		}
+		intent, err := scanPaymentIntent(tx.QueryRowContext(ctx, `
+UPDATE payment_intents
+SET applied_at = NOW()
+WHERE subscription_id = $1 AND purpose = 'subscribe' AND applied_at IS NULL AND status = 'captured'
+RETURNING `+paymentIntentColumns, subId))
+		switch {
+		case err == nil:
+			err = issuePaymentInvoice(ctx, tx, intent, data.InvoiceSubscribe, 0)
+			if err != nil {
+				return err
+			}
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventActivated, 0, "")
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%s:%w", "storage.postgres.ActivateSubscription", ErrSubNotFound)
+		default:
+			return fmt.Errorf("%s:%w", "storage.postgres.ActivateSubscription", err)
+		}
+	}
+	return nil
+}
+
+// FailPendingSubscription compensates a pending subscription whose payment
+// failed or whose bucket could not be provisioned. The row is kept as failed
+// for history, but everything the subscribe granted is taken back: the
+// balance is reversed in the ledger, the trial and coupon redemptions are
+// released so that the user can subscribe again on the same terms, and a
+// payment still open is cancelled. Refunding a captured payment is up to the
+// caller.
+func (s *Storage) FailPendingSubscription(ctx context.Context, subId int64, reason string) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var userId, previousLimit int64
+		var trialEndsAt sql.NullTime
+		err := tx.QueryRowContext(ctx, `SELECT user_id, remaining_limit, trial_ends_at FROM subscriptions WHERE id = $1 AND status = 'pending' FOR UPDATE`, subId).Scan(&userId, &previousLimit, &trialEndsAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+UPDATE subscriptions
+SET status = 'failed', remaining_limit = 0, discount_amount = 0, trial_ends_at = NULL
+WHERE id = $1
+`, subId)
+		if err != nil {
+			return err
+		}
+
+		if trialEndsAt.Valid {
+			_, err = tx.ExecContext(ctx, `DELETE FROM trial_redemptions WHERE user_id = $1`, userId)
+			if err != nil {
+				return err
+			}
+		}
+		// The subscribe started a new period, so redemptions since then belong
+		// to this attempt.
+		_, err = tx.ExecContext(ctx, `
+DELETE FROM coupon_redemptions
+WHERE subscription_id = $1 AND redeemed_at >= (SELECT period_started_at FROM subscriptions WHERE id = $1)
+`, subId)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+UPDATE payment_intents
+SET status = 'cancelled', updated_at = NOW()
+WHERE subscription_id = $1 AND status IN ('created', 'authorized')
+`, subId)
+		if err != nil {
+			return err
+		}
+
+		_, err = insertLedgerEntry(ctx, tx, subId, -previousLimit, data.AccountPlanAllowance, data.EventProvisioningFailed, "", 0)
+		if err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, subId, data.EventProvisioningFailed, 0, reason)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%s:%w", "storage.postgres.FailPendingSubscription", ErrSubNotFound)
+		default:
+			return fmt.Errorf("%s:%w", "storage.postgres.FailPendingSubscription", err)
+		}
+	}
+	return nil
+}
+
+// ListStalePendingSubscriptions returns up to limit subscriptions that have
+// been pending since before the given time, which means the process handling
+// their subscribe stopped before provisioning finished.
+func (s *Storage) ListStalePendingSubscriptions(ctx context.Context, before time.Time, limit int) ([]data.Subscription, error) {
+	query := `
+SELECT id, user_id, plan_id, remaining_limit, expires_at, status
+FROM subscriptions
+WHERE status = 'pending' AND period_started_at < $1
+ORDER BY period_started_at
+LIMIT $2
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ListStalePendingSubscriptions", err)
+	}
+	defer rows.Close()
+
+	stale := []data.Subscription{}
+	for rows.Next() {
+		var sub data.Subscription
+		err := rows.Scan(
+			&sub.ID,
+			&sub.UserID,
+			&sub.PlanID,
+			&sub.RemainingLimit,
+			&sub.ExpiresAt,
+			&sub.Status,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.ListStalePendingSubscriptions", err)
+		}
+		stale = append(stale, sub)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ListStalePendingSubscriptions", err)
+	}
+	return stale, nil
+}
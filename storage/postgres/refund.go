@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"subscriptionMService/internal/money"
+	"time"
+)
+
+// GetLatestPaidIntent returns the subscription's most recent subscribe or
+// renewal payment that was applied and still has something left to refund,
+// or ErrPaymentIntentNotFound.
+func (s *Storage) GetLatestPaidIntent(ctx context.Context, subId int64) (*data.PaymentIntent, error) {
+	query := `
+SELECT ` + paymentIntentColumns + `
+FROM payment_intents
+WHERE subscription_id = $1 AND purpose IN ('subscribe', 'renewal') AND status = 'captured'
+  AND applied_at IS NOT NULL AND refunded_amount < amount
+ORDER BY id DESC
+LIMIT 1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	intent, err := scanPaymentIntent(s.db.QueryRowContext(ctx, query, subId))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetLatestPaidIntent", ErrPaymentIntentNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetLatestPaidIntent", err)
+		}
+	}
+	return intent, nil
+}
+
+// ReserveRefund records the refund described by audit before it is paid out.
+// With the intent locked, it adds the amount to what has been refunded of the
+// intent, issues a credit note, stores the audit record and ends the
+// subscription's access straight away, even if it was cancelled to run until
+// the end of its period, since that period is being paid back. It returns the updated intent, or ErrNothingToRefund if
+// the intent no longer covers the amount or the subscription has already
+// ended, for instance because a concurrent refund got there first.
+func (s *Storage) ReserveRefund(ctx context.Context, audit *data.RefundAudit) (*data.PaymentIntent, error) {
+	query := `
+INSERT INTO refund_audits (subscription_id, user_id, actor_id, reason_code, note, policy, payment_intent_id,
+                           paid_amount, unused_time_bp, unused_limit_bp, amount, currency)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, created_at
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	args := []any{
+		audit.SubscriptionID,
+		audit.UserID,
+		audit.ActorID,
+		audit.ReasonCode,
+		audit.Note,
+		audit.Quote.Policy,
+		audit.PaymentIntentID,
+		audit.Quote.Paid,
+		money.Round(audit.Quote.UnusedTime * 10000),
+		money.Round(audit.Quote.UnusedLimit * 10000),
+		audit.Quote.Amount,
+		audit.Currency,
+	}
+
+	var intent *data.PaymentIntent
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var status string
+		var amount, refunded int64
+		err := tx.QueryRowContext(ctx, `SELECT status, amount, refunded_amount FROM payment_intents WHERE id = $1 FOR UPDATE`,
+			audit.PaymentIntentID).Scan(&status, &amount, &refunded)
+		if err != nil {
+			return err
+		}
+		if status != data.PaymentCaptured || refunded+audit.Quote.Amount > amount {
+			return ErrNothingToRefund
+		}
+
+		ended, err := expireRefunded(ctx, tx, audit.SubscriptionID, audit.ReasonCode)
+		if err != nil {
+			return err
+		}
+		if !ended {
+			return ErrNothingToRefund
+		}
+
+		intent, err = scanPaymentIntent(tx.QueryRowContext(ctx, `
+UPDATE payment_intents
+SET refunded_amount = refunded_amount + $2,
+    status = CASE WHEN refunded_amount + $2 = amount THEN 'refunded' ELSE status END,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING `+paymentIntentColumns, audit.PaymentIntentID, audit.Quote.Amount))
+		if err != nil {
+			return err
+		}
+		if err := issueCreditNote(ctx, tx, intent, audit.Quote.Amount); err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&audit.ID, &audit.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, audit.SubscriptionID, data.EventRefunded, audit.Quote.Amount, audit.ReasonCode)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ReserveRefund", err)
+	}
+	return intent, nil
+}
+
+// MarkRefundPaidOut notes that the refund reserved for audit has reached the
+// payer. Audit records left without it were reserved but never paid out.
+func (s *Storage) MarkRefundPaidOut(ctx context.Context, audit *data.RefundAudit) error {
+	query := `
+UPDATE refund_audits
+SET paid_out_at = NOW()
+WHERE id = $1
+RETURNING paid_out_at
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if err := s.db.QueryRowContext(ctx, query, audit.ID).Scan(&audit.PaidOutAt); err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.MarkRefundPaidOut", err)
+	}
+	return nil
+}
+
+// endRefundedPeriod ends the access intent paid for once it has been refunded
+// in full, provided it paid for the subscription's current period, and records
+// the refund of amount in the subscription's history.
+func endRefundedPeriod(ctx context.Context, tx *sql.Tx, intent *data.PaymentIntent, amount int64) error {
+	var current bool
+	err := tx.QueryRowContext(ctx, `
+SELECT NOT EXISTS (
+    SELECT 1 FROM payment_intents
+    WHERE subscription_id = $1 AND purpose IN ('subscribe', 'renewal') AND applied_at IS NOT NULL AND id > $2
+)
+`, intent.SubscriptionID, intent.ID).Scan(&current)
+	if err != nil || !current {
+		return err
+	}
+
+	ended, err := expireRefunded(ctx, tx, intent.SubscriptionID, data.EventRefunded)
+	if err != nil || !ended {
+		return err
+	}
+	return insertSubEvent(ctx, tx, intent.SubscriptionID, data.EventRefunded, amount, "refunded at the gateway")
+}
+
+// expireRefunded ends the access of a subscription whose period is being paid
+// back, even if it was cancelled to run until the end of that period, and
+// stops any payment retries. It reports false if the subscription had already
+// ended.
+func expireRefunded(ctx context.Context, tx *sql.Tx, subId int64, reason string) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+UPDATE subscriptions
+SET status = 'expired', expires_at = LEAST(expires_at, NOW()), grace_until = NULL, past_due_since = NULL,
+    cancelled_at = COALESCE(cancelled_at, NOW()), cancel_reason = COALESCE(cancel_reason, $2)
+WHERE id = $1 AND status NOT IN ('expired', 'failed', 'pending')
+`, subId, reason)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return false, err
+	}
+	return true, cancelDunning(ctx, tx, subId)
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// QueueReminders finds up to limit subscriptions that should be reminded, as
+// configured on their plan: ones expiring within expiry_reminder_days and ones
+// whose remaining limit has dropped to low_limit_threshold. Each reminder is
+// sent at most once per kind per billing period, tracked in reminders_sent,
+// and is queued as a reminder event in the outbox for the notifications
+// dispatcher.
+func (s *Storage) QueueReminders(ctx context.Context, now time.Time, limit int) ([]data.Reminder, error) {
+	query := `
+INSERT INTO reminders_sent (subscription_id, kind, period_started_at)
+SELECT due.id, due.kind, due.period_started_at
+FROM (
+    SELECT s.id, s.user_id, s.period_started_at, s.expires_at AS due_at,
+           CASE WHEN p.auto_renew AND s.status IN ('active', 'trialing') THEN 'renewal_upcoming' ELSE 'expiring' END AS kind
+    FROM subscriptions s
+    JOIN subscription_plans p ON p.id = s.plan_id
+    WHERE s.status IN ('active', 'trialing', 'cancelled')
+      AND p.expiry_reminder_days > 0
+      AND s.expires_at > $1
+      AND s.expires_at <= $1 + p.expiry_reminder_days * INTERVAL '1 day'
+    UNION ALL
+    SELECT s.id, s.user_id, s.period_started_at, s.expires_at AS due_at, 'low_limit' AS kind
+    FROM subscriptions s
+    JOIN subscription_plans p ON p.id = s.plan_id
+    WHERE s.status IN ('active', 'trialing', 'grace')
+      AND s.remaining_limit <= p.low_limit_threshold
+) due
+WHERE NOT EXISTS (
+    SELECT 1 FROM reminders_sent r
+    WHERE r.subscription_id = due.id AND r.kind = due.kind AND r.period_started_at = due.period_started_at
+)
+ORDER BY due.due_at
+LIMIT $2
+ON CONFLICT DO NOTHING
+RETURNING subscription_id, kind, period_started_at
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var reminders []data.Reminder
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, now, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		reminders = []data.Reminder{}
+		for rows.Next() {
+			var reminder data.Reminder
+			err := rows.Scan(&reminder.SubscriptionID, &reminder.Kind, &reminder.PeriodStartedAt)
+			if err != nil {
+				return err
+			}
+			reminders = append(reminders, reminder)
+		}
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		for _, reminder := range reminders {
+			err := insertSubEvent(ctx, tx, reminder.SubscriptionID, data.EventReminder, 0, reminder.Kind)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.QueueReminders", err)
+	}
+	return reminders, nil
+}
@@ -0,0 +1,301 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
+	"strconv"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+const (
+	renewalSucceeded = "succeeded"
+	renewalFailed    = "failed"
+)
+
+// StartRenewal picks one subscription due for renewal, moves it to its
+// pending or current plan version, and records a payment intent for the
+// price, plus anything outstanding, less its coupon discount. Ended trials,
+// subscriptions in grace and past due ones with a retry due are picked too. Subscriptions
+// tried less than retryAfter ago are skipped, and an intent left open by an
+// interrupted renewal is returned again. It returns nil when nothing is due.
+func (s *Storage) StartRenewal(ctx context.Context, now time.Time, retryAfter time.Duration) (*data.PaymentIntent, error) {
+	query := `
+SELECT s.id, s.user_id, np.id, npp.amount_minor + s.outstanding_amount, s.discount_amount, s.currency
+FROM subscriptions s
+JOIN subscription_plans p ON p.id = s.plan_id
+JOIN subscription_plans tp ON tp.id = COALESCE(s.pending_plan_id, s.plan_id)
+JOIN LATERAL (
+    SELECT lp.id
+    FROM subscription_plans lp
+    WHERE lp.family_id = tp.family_id
+      AND (lp.id = tp.id OR (NOT tp.grandfathered AND lp.archived_at IS NULL
+        AND EXISTS (SELECT 1 FROM plan_prices WHERE plan_id = lp.id AND currency = s.currency)))
+    ORDER BY lp.version DESC
+    LIMIT 1
+) np ON TRUE
+JOIN plan_prices npp ON npp.plan_id = np.id AND npp.currency = s.currency
+WHERE ((p.auto_renew
+    AND ((s.status = 'active' AND s.expires_at <= $1 + p.renewal_lead_hours * INTERVAL '1 hour')
+      OR (s.status = 'grace' AND s.grace_until > $1)))
+    OR (s.status = 'trialing' AND s.expires_at <= $1)
+    OR (s.status = 'past_due' AND EXISTS (
+      SELECT 1 FROM dunning_attempts da
+      WHERE da.subscription_id = s.id AND da.status = 'scheduled' AND da.scheduled_at <= $1)))
+  AND (s.renewal_attempted_at IS NULL OR s.renewal_attempted_at <= $1 - $2 * INTERVAL '1 second')
+ORDER BY s.expires_at
+LIMIT 1
+FOR UPDATE OF s SKIP LOCKED
+`
+	openQuery := `
+SELECT ` + paymentIntentColumns + `
+FROM payment_intents
+WHERE subscription_id = $1 AND purpose = 'renewal' AND applied_at IS NULL
+  AND status IN ('created', 'authorized', 'captured')
+ORDER BY id DESC
+LIMIT 1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var intent *data.PaymentIntent
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var gross, discount int64
+		i := data.PaymentIntent{Purpose: data.PaymentRenewal}
+
+		err := tx.QueryRowContext(ctx, query, now, retryAfter.Seconds()).Scan(
+			&i.SubscriptionID,
+			&i.UserID,
+			&i.PlanID,
+			&gross,
+			&discount,
+			&i.Currency,
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE subscriptions SET renewal_attempted_at = $1 WHERE id = $2`, now, i.SubscriptionID)
+		if err != nil {
+			return err
+		}
+
+		intent, err = scanPaymentIntent(tx.QueryRowContext(ctx, openQuery, i.SubscriptionID))
+		if err == nil || !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		i.DiscountApplied = min(discount, gross)
+		i.Amount = gross - i.DiscountApplied
+		if err := insertPaymentIntent(ctx, tx, &i); err != nil {
+			return err
+		}
+		intent = &i
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.StartRenewal", err)
+	}
+	return intent, nil
+}
+
+// CompleteRenewal applies a captured renewal payment: the period is extended,
+// the rental limit reset to the renewed plan's and the coupon discount used
+// by the payment consumed. The new period is invoiced, and open proration
+// invoices are paid by the same payment. A past due subscription leaves
+// dunning, with the retry that paid marked succeeded.
+func (s *Storage) CompleteRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error) {
+	query := `
+SELECT pi.subscription_id, pi.user_id, pi.plan_id, pi.amount, pi.discount_applied, s.expires_at, s.remaining_limit,
+       s.status, COALESCE(s.pending_plan_id, s.plan_id), s.outstanding_amount, np.rental_limit, np.duration_months
+FROM payment_intents pi
+JOIN subscriptions s ON s.id = pi.subscription_id
+JOIN subscription_plans np ON np.id = pi.plan_id
+WHERE pi.id = $1 AND pi.purpose = 'renewal' AND pi.status = 'captured' AND pi.applied_at IS NULL
+FOR UPDATE OF pi, s
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var renewal *data.Renewal
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var expiresAt time.Time
+		var previousLimit, discountApplied, outstanding int64
+		var previousStatus string
+		var targetPlanID, rentalLimit int32
+		var duration subs.Duration
+		r := data.Renewal{Status: renewalSucceeded}
+
+		err := tx.QueryRowContext(ctx, query, intentId).Scan(
+			&r.SubscriptionID,
+			&r.UserID,
+			&r.PlanID,
+			&r.Amount,
+			&discountApplied,
+			&expiresAt,
+			&previousLimit,
+			&previousStatus,
+			&targetPlanID,
+			&outstanding,
+			&rentalLimit,
+			&duration,
+		)
+		if err != nil {
+			return err
+		}
+
+		r.ExpiresAt = addMonths(expiresAt, duration)
+		_, err = tx.ExecContext(ctx, `
+UPDATE subscriptions
+SET status = 'active', plan_id = $1, period_started_at = expires_at, expires_at = $2, remaining_limit = $3,
+    grace_until = NULL, past_due_since = NULL, pending_plan_id = NULL, pending_change_at = NULL, outstanding_amount = 0,
+    discount_amount = GREATEST(discount_amount - $4, 0)
+WHERE id = $5
+`, r.PlanID, r.ExpiresAt, rentalLimit, discountApplied, r.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		_, err = insertLedgerEntry(ctx, tx, r.SubscriptionID, int64(rentalLimit)-previousLimit, data.AccountPlanAllowance, data.EventRenewed, "", int64(rentalLimit))
+		if err != nil {
+			return err
+		}
+
+		eventType, details := data.EventRenewed, ""
+		switch previousStatus {
+		case "trialing":
+			eventType = data.EventTrialConverted
+		case "past_due":
+			details = data.DunningRecovered
+		}
+		if err := settleDunningAttempt(ctx, tx, r.SubscriptionID, intentId, data.DunningSucceeded, "", now); err != nil {
+			return err
+		}
+		if err := insertSubEvent(ctx, tx, r.SubscriptionID, eventType, r.Amount, details); err != nil {
+			return err
+		}
+		if r.PlanID != targetPlanID {
+			err = insertSubEvent(ctx, tx, r.SubscriptionID, data.EventPlanMigrated, 0, strconv.Itoa(int(targetPlanID)))
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := finishRenewal(ctx, tx, intentId, &r); err != nil {
+			return err
+		}
+		intent, err := scanPaymentIntent(tx.QueryRowContext(ctx, `SELECT `+paymentIntentColumns+` FROM payment_intents WHERE id = $1`, intentId))
+		if err != nil {
+			return err
+		}
+		if err := issuePaymentInvoice(ctx, tx, intent, data.InvoiceRenewal, outstanding); err != nil {
+			return err
+		}
+		renewal = &r
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.CompleteRenewal", ErrPaymentIntentNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.CompleteRenewal", err)
+		}
+	}
+	return renewal, nil
+}
+
+// FailRenewal applies a failed renewal payment: the subscription enters its
+// plan's grace period, keeping access while the charge is retried, or expires
+// straight away if it was a trial. Dunning only starts once grace runs out,
+// through BeginDunning; a failed retry of a past due subscription is recorded
+// against its dunning attempt.
+func (s *Storage) FailRenewal(ctx context.Context, intentId int64, now time.Time) (*data.Renewal, error) {
+	query := `
+SELECT pi.subscription_id, pi.user_id, pi.plan_id, pi.amount, pi.failure_reason, s.expires_at, s.status,
+       p.grace_period_days
+FROM payment_intents pi
+JOIN subscriptions s ON s.id = pi.subscription_id
+JOIN subscription_plans p ON p.id = s.plan_id
+WHERE pi.id = $1 AND pi.purpose = 'renewal' AND pi.status = 'failed' AND pi.applied_at IS NULL
+FOR UPDATE OF pi, s
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var renewal *data.Renewal
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var previousStatus string
+		var graceDays int32
+		r := data.Renewal{Status: renewalFailed}
+
+		err := tx.QueryRowContext(ctx, query, intentId).Scan(
+			&r.SubscriptionID,
+			&r.UserID,
+			&r.PlanID,
+			&r.Amount,
+			&r.Reason,
+			&r.ExpiresAt,
+			&previousStatus,
+			&graceDays,
+		)
+		if err != nil {
+			return err
+		}
+
+		eventType := data.EventRenewalFailed
+		switch previousStatus {
+		case "trialing":
+			eventType = data.EventExpired
+			r.Reason += " at trial end"
+			_, err = tx.ExecContext(ctx, `UPDATE subscriptions SET status = 'expired' WHERE id = $1`, r.SubscriptionID)
+		case "past_due":
+			err = settleDunningAttempt(ctx, tx, r.SubscriptionID, intentId, data.DunningFailed, r.Reason, now)
+		default:
+			_, err = tx.ExecContext(ctx, `
+UPDATE subscriptions
+SET status = 'grace', grace_until = COALESCE(grace_until, $1)
+WHERE id = $2
+`, r.ExpiresAt.AddDate(0, 0, int(graceDays)), r.SubscriptionID)
+		}
+		if err != nil {
+			return err
+		}
+		if err := insertSubEvent(ctx, tx, r.SubscriptionID, eventType, r.Amount, r.Reason); err != nil {
+			return err
+		}
+
+		if err := finishRenewal(ctx, tx, intentId, &r); err != nil {
+			return err
+		}
+		renewal = &r
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.FailRenewal", ErrPaymentIntentNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.FailRenewal", err)
+		}
+	}
+	return renewal, nil
+}
+
+// finishRenewal marks the intent applied and records the renewal attempt.
+func finishRenewal(ctx context.Context, tx *sql.Tx, intentId int64, r *data.Renewal) error {
+	_, err := tx.ExecContext(ctx, `UPDATE payment_intents SET applied_at = NOW() WHERE id = $1`, intentId)
+	if err != nil {
+		return err
+	}
+	return tx.QueryRowContext(ctx, `
+INSERT INTO subscription_renewals (subscription_id, user_id, plan_id, amount, status, reason, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id
+`, r.SubscriptionID, r.UserID, r.PlanID, r.Amount, r.Status, r.Reason, r.ExpiresAt).Scan(&r.ID)
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	subs "github.com/spacecowboytobykty123/subsProto/gen/go/subscription"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+// RecordRetentionOffer stores offer for its subscription's current period and
+// fills in its id, status and offer time. If an offer was already made this
+// period the stored one is returned in its place, so previewing twice shows
+// the same offer; once that offer has been accepted or declined it returns
+// ErrRetentionOfferUsed.
+func (s *Storage) RecordRetentionOffer(ctx context.Context, offer *data.RetentionOffer) error {
+	insertQuery := `
+INSERT INTO retention_offers (subscription_id, user_id, plan_id, rule, kind, discount_amount, bonus_limit, free_months, period_started_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (subscription_id, period_started_at) DO NOTHING
+RETURNING id, status, offered_at
+`
+	existingQuery := `
+SELECT id, plan_id, rule, kind, discount_amount, bonus_limit, free_months, status, offered_at
+FROM retention_offers
+WHERE subscription_id = $1 AND period_started_at = $2
+`
+	args := []any{
+		offer.SubscriptionID,
+		offer.UserID,
+		offer.PlanID,
+		offer.Rule,
+		offer.Kind,
+		offer.DiscountAmount,
+		offer.BonusLimit,
+		offer.FreeMonths,
+		offer.PeriodStartedAt,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, insertQuery, args...).Scan(&offer.ID, &offer.Status, &offer.OfferedAt)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s:%w", "storage.postgres.RecordRetentionOffer", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, existingQuery, offer.SubscriptionID, offer.PeriodStartedAt).Scan(
+		&offer.ID,
+		&offer.PlanID,
+		&offer.Rule,
+		&offer.Kind,
+		&offer.DiscountAmount,
+		&offer.BonusLimit,
+		&offer.FreeMonths,
+		&offer.Status,
+		&offer.OfferedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.RecordRetentionOffer", err)
+	}
+	if offer.Status != data.RetentionOffered {
+		return fmt.Errorf("%s:%w", "storage.postgres.RecordRetentionOffer", ErrRetentionOfferUsed)
+	}
+	return nil
+}
+
+// AcceptRetentionOffer applies the user's open retention offer to their
+// subscription instead of cancelling it. The offer only applies within the
+// period it was made for.
+func (s *Storage) AcceptRetentionOffer(ctx context.Context, userId, offerId int64) subs.Status {
+	acceptQuery := `
+UPDATE retention_offers
+SET status = 'accepted', responded_at = NOW()
+WHERE id = $1 AND user_id = $2 AND status = 'offered'
+RETURNING subscription_id, kind, discount_amount, bonus_limit, free_months, period_started_at
+`
+	applyQuery := `
+UPDATE subscriptions
+SET discount_amount = discount_amount + $1,
+    remaining_limit = remaining_limit + $2,
+    expires_at = expires_at + $3 * INTERVAL '1 month'
+WHERE id = $4 AND period_started_at = $5 AND status IN ('active', 'grace')
+RETURNING remaining_limit
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var offer data.RetentionOffer
+		err := tx.QueryRowContext(ctx, acceptQuery, offerId, userId).Scan(
+			&offer.SubscriptionID,
+			&offer.Kind,
+			&offer.DiscountAmount,
+			&offer.BonusLimit,
+			&offer.FreeMonths,
+			&offer.PeriodStartedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		var remainingLimit int64
+		args := []any{offer.DiscountAmount, offer.BonusLimit, offer.FreeMonths, offer.SubscriptionID, offer.PeriodStartedAt}
+		err = tx.QueryRowContext(ctx, applyQuery, args...).Scan(&remainingLimit)
+		if err != nil {
+			return err
+		}
+
+		reference := fmt.Sprint(offerId)
+		_, err = insertLedgerEntry(ctx, tx, offer.SubscriptionID, int64(offer.BonusLimit), data.AccountPromotions, data.EventRetentionAccepted, reference, remainingLimit)
+		if err != nil {
+			return err
+		}
+		return insertSubEvent(ctx, tx, offer.SubscriptionID, data.EventRetentionAccepted, offer.DiscountAmount, offer.Kind)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return subs.Status_STATUS_SUBSCRIPTION_NOTFOUND
+		default:
+			println(err.Error())
+			return subs.Status_STATUS_INTERNAL_ERROR
+		}
+	}
+	return subs.Status_STATUS_OK
+}
+
+// declineRetentionOffers marks the subscription's open offers as declined. It
+// runs in the transaction that cancels the subscription.
+func declineRetentionOffers(ctx context.Context, tx *sql.Tx, subId int64) error {
+	query := `
+UPDATE retention_offers
+SET status = 'declined', responded_at = NOW()
+WHERE subscription_id = $1 AND status = 'offered'
+`
+	_, err := tx.ExecContext(ctx, query, subId)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.declineRetentionOffers", err)
+	}
+	return nil
+}
@@ -6,4 +6,27 @@ var (
 	ErrUserSubscribed = errors.New("user already subscribed")
 	ErrPlanNotFound   = errors.New("plan not found")
 	ErrSubNotFound    = errors.New("subscription not found")
+
+	ErrCouponNotFound      = errors.New("coupon not found")
+	ErrCouponDisabled      = errors.New("coupon disabled")
+	ErrCouponNotActive     = errors.New("coupon not valid at this time")
+	ErrCouponWrongPlan     = errors.New("coupon not valid for plan")
+	ErrCouponWrongCurrency = errors.New("coupon not valid for currency")
+	ErrCouponExhausted     = errors.New("coupon fully redeemed")
+	ErrCouponAlreadyUsed   = errors.New("coupon already used by user")
+	ErrCouponCodeConflict  = errors.New("coupon code already exists")
+
+	ErrRetentionOfferUsed = errors.New("retention offer already used this period")
+
+	ErrContactNotFound = errors.New("contact not found")
+
+	ErrPaymentIntentNotFound    = errors.New("payment intent not found")
+	ErrInvalidPaymentTransition = errors.New("invalid payment intent transition")
+	ErrNothingToRefund          = errors.New("nothing left to refund")
+	ErrWalletNotFound           = errors.New("wallet not found")
+	ErrWalletCurrency           = errors.New("wallet held in another currency")
+	ErrWebhookNotFound          = errors.New("webhook event not found")
+
+	ErrInvoiceNotFound          = errors.New("invoice not found")
+	ErrInvalidInvoiceTransition = errors.New("invalid invoice transition")
 )
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+// withTx runs fn inside a transaction, committing on success and rolling
+// back if fn returns an error.
+func (s *Storage) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
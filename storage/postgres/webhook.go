@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"subscriptionMService/internal/data"
+	"time"
+)
+
+const webhookColumns = `id, event_type, provider_ref, payload, attempts, last_error, received_at, processed_at`
+
+func scanWebhook(row interface{ Scan(...any) error }) (*data.WebhookDelivery, error) {
+	var delivery data.WebhookDelivery
+	var processedAt sql.NullTime
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.Type,
+		&delivery.ProviderRef,
+		&delivery.Payload,
+		&delivery.Attempts,
+		&delivery.LastError,
+		&delivery.ReceivedAt,
+		&processedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	delivery.ProcessedAt = processedAt.Time
+	return &delivery, nil
+}
+
+// RecordWebhook stores a received webhook under its event id. A redelivery of
+// an event that has not been processed yet counts another attempt. It reports
+// false if the event has already been processed, so it must not be applied
+// again.
+func (s *Storage) RecordWebhook(ctx context.Context, delivery *data.WebhookDelivery) (bool, error) {
+	query := `
+INSERT INTO webhook_events (id, event_type, provider_ref, payload)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO UPDATE SET attempts = webhook_events.attempts + 1
+WHERE webhook_events.processed_at IS NULL
+RETURNING attempts, received_at
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	args := []any{delivery.ID, delivery.Type, delivery.ProviderRef, delivery.Payload}
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&delivery.Attempts, &delivery.ReceivedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return false, nil
+		default:
+			return false, fmt.Errorf("%s:%w", "storage.postgres.RecordWebhook", err)
+		}
+	}
+	return true, nil
+}
+
+// FinishWebhook records the outcome of applying a webhook. An empty
+// processErr marks it processed; otherwise the error is kept for replay.
+func (s *Storage) FinishWebhook(ctx context.Context, id string, processErr string) error {
+	query := `
+UPDATE webhook_events
+SET processed_at = CASE WHEN $2 = '' THEN NOW() END, last_error = $2
+WHERE id = $1
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, query, id, processErr)
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.FinishWebhook", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s:%w", "storage.postgres.FinishWebhook", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s:%w", "storage.postgres.FinishWebhook", ErrWebhookNotFound)
+	}
+	return nil
+}
+
+// GetWebhook returns a stored webhook by event id.
+func (s *Storage) GetWebhook(ctx context.Context, id string) (*data.WebhookDelivery, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhook_events WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	delivery, err := scanWebhook(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetWebhook", ErrWebhookNotFound)
+		default:
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.GetWebhook", err)
+		}
+	}
+	return delivery, nil
+}
+
+// ListWebhooks returns up to limit webhooks received at or after since, oldest
+// first. With unprocessedOnly set, webhooks already applied are left out.
+func (s *Storage) ListWebhooks(ctx context.Context, since time.Time, unprocessedOnly bool, limit int) ([]*data.WebhookDelivery, error) {
+	query := `
+SELECT ` + webhookColumns + `
+FROM webhook_events
+WHERE received_at >= $1 AND (NOT $2 OR processed_at IS NULL)
+ORDER BY received_at, id
+LIMIT $3
+`
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, since, unprocessedOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ListWebhooks", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*data.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%w", "storage.postgres.ListWebhooks", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s:%w", "storage.postgres.ListWebhooks", err)
+	}
+	return deliveries, nil
+}